@@ -62,7 +62,7 @@ func run(src string, env *runtime.Environment) {
 
 			if err != nil {
 				fmt.Println(err.Error())
-			} else if res != nil {
+			} else if res != nil && res.GetType() != runtime.NullVT {
 				fmt.Println(res.Print())
 			}
 		}
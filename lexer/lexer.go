@@ -36,6 +36,14 @@ func (lex *Lexer) advance() {
 	}
 }
 
+func (lex *Lexer) peek() string {
+	if lex.currentPosition+1 < len(lex.text) {
+		return lex.text[lex.currentPosition+1]
+	}
+
+	return ""
+}
+
 func (lex *Lexer) isSkippable(char string) bool {
 	return char == " " || char == "\t" || char == "\n" || char == "\r"
 }
@@ -54,7 +62,7 @@ func (lex *Lexer) makeNumber() *Token {
 
 	for lex.currentChar != "" && (lex.isDigit(lex.currentChar) || lex.currentChar == ".") {
 		if lex.currentChar == "." {
-			if dotCount == 1 {
+			if dotCount == 1 || lex.peek() == "." { // '1..5' is a range, not a float
 				break
 			}
 			dotCount++
@@ -65,6 +73,18 @@ func (lex *Lexer) makeNumber() *Token {
 		lex.advance()
 	}
 
+	// a 'd' suffix, as in '1.10d', makes an exact decimal and an 'i'
+	// suffix, as in '3i', an imaginary number
+	if (lex.currentChar == "d" || lex.currentChar == "i") && !lex.isAlpha(lex.peek()) && !lex.isDigit(lex.peek()) {
+		tt := DecimalTT
+		if lex.currentChar == "i" {
+			tt = ImaginaryTT
+		}
+
+		lex.advance()
+		return NewToken(tt, numString)
+	}
+
 	if dotCount == 0 {
 		return NewToken(IntTT, numString)
 	} else {
@@ -123,6 +143,10 @@ func (lex *Lexer) makeLessThan() *Token {
 		lex.advance()
 		tt = LessThanEqualsTT
 		val = "<="
+	} else if lex.currentChar == "<" {
+		lex.advance()
+		tt = DoubleLessThanTT
+		val = "<<"
 	}
 
 	return NewToken(tt, val)
@@ -138,11 +162,89 @@ func (lex *Lexer) makeGreaterThan() *Token {
 		lex.advance()
 		tt = GreaterThanEqualsTT
 		val = ">="
+	} else if lex.currentChar == ">" {
+		lex.advance()
+		tt = DoubleGreaterThanTT
+		val = ">>"
+	}
+
+	return NewToken(tt, val)
+}
+
+func (lex *Lexer) makeBarOrPipe() *Token {
+	lex.advance()
+
+	if lex.currentChar == ">" {
+		lex.advance()
+		return NewToken(PipeTT, "|>")
+	}
+
+	return NewToken(BarTT, "|")
+}
+
+func (lex *Lexer) makeSlash() *Token {
+	lex.advance()
+
+	tt := DivideTT
+	val := "/"
+
+	if lex.currentChar == "/" {
+		lex.advance()
+		tt = DoubleSlashTT
+		val = "//"
+	} else if lex.currentChar == "=" {
+		lex.advance()
+		tt = DivideEqualsTT
+		val = "/="
 	}
 
 	return NewToken(tt, val)
 }
 
+func (lex *Lexer) makeQuestion() (*Token, error) {
+	lex.advance()
+
+	if lex.currentChar == "?" {
+		lex.advance()
+		return NewToken(DoubleQuestionTT, "??"), nil
+	} else if lex.currentChar == "." {
+		lex.advance()
+		return NewToken(QuestionDotTT, "?."), nil
+	} else if lex.currentChar == "[" {
+		return NewToken(QuestionTT, "?"), nil
+	}
+
+	return nil, utils.ExpectedCharError("'.', '[' or '?' (after '?')")
+}
+
+// makeOperator creates the token of an arithmetic operator or, when it is
+// followed by '=', of the corresponding compound assignment
+func (lex *Lexer) makeOperator(tt TokenType, assignTT TokenType) *Token {
+	val := lex.currentChar
+	lex.advance()
+
+	if lex.currentChar == "=" {
+		lex.advance()
+		return NewToken(assignTT, val+"=")
+	}
+
+	return NewToken(tt, val)
+}
+
+func (lex *Lexer) makePlus() *Token {
+	lex.advance()
+
+	if lex.currentChar == "+" {
+		lex.advance()
+		return NewToken(DoublePlusTT, "++")
+	} else if lex.currentChar == "=" {
+		lex.advance()
+		return NewToken(PlusEqualsTT, "+=")
+	}
+
+	return NewToken(PlusTT, "+")
+}
+
 func (lex *Lexer) makeMinusOrArrow() *Token {
 	lex.advance()
 	tt := MinusTT
@@ -152,13 +254,41 @@ func (lex *Lexer) makeMinusOrArrow() *Token {
 		lex.advance()
 		tt = ArrowTT
 		val = "->"
+	} else if lex.currentChar == "=" {
+		lex.advance()
+		tt = MinusEqualsTT
+		val = "-="
+	} else if lex.currentChar == "-" {
+		lex.advance()
+		tt = DoubleMinusTT
+		val = "--"
 	}
 
 	return NewToken(tt, val)
 
 }
 
-func (lex *Lexer) makeString() *Token {
+func (lex *Lexer) makeDot() *Token {
+	lex.advance()
+
+	if lex.currentChar != "." {
+		return NewToken(DotTT, ".")
+	}
+
+	lex.advance()
+
+	if lex.currentChar == "." {
+		lex.advance()
+		return NewToken(EllipsisTT, "...")
+	} else if lex.currentChar == "=" {
+		lex.advance()
+		return NewToken(DoubleDotEqualsTT, "..=")
+	}
+
+	return NewToken(DoubleDotTT, "..")
+}
+
+func (lex *Lexer) makeString() ([]*Token, error) {
 	str := ""
 	escapeChar := false
 	lex.advance()
@@ -168,6 +298,10 @@ func (lex *Lexer) makeString() *Token {
 		"t": "\t",
 	}
 
+	// parts collects the tokens of an interpolated string, it stays nil
+	// as long as no '${' is found
+	var parts []*Token
+
 	for lex.currentChar != "" && (lex.currentChar != "\"" || escapeChar) {
 		if escapeChar {
 			rep, found := escapeChars[lex.currentChar]
@@ -176,51 +310,88 @@ func (lex *Lexer) makeString() *Token {
 			} else {
 				str += lex.currentChar
 			}
-		} else {
-			if lex.currentChar == "\\" {
-				escapeChar = true
-			} else {
-				str += lex.currentChar
+			escapeChar = false
+		} else if lex.currentChar == "\\" {
+			escapeChar = true
+		} else if lex.currentChar == "$" && lex.peek() == "{" {
+			lex.advance()
+			lex.advance()
+
+			exprTokens, err := lex.tokenize(true)
+			if err != nil {
+				return nil, err
+			}
+
+			if lex.currentChar != "}" {
+				return nil, utils.ExpectedCharError("'}' (after '${')")
 			}
+
+			parts = append(parts, NewToken(StringTT, str), NewToken(InterpOpenTT, "${"))
+			parts = append(parts, exprTokens...)
+			parts = append(parts, NewToken(InterpCloseTT, "}"))
+			str = ""
+		} else {
+			str += lex.currentChar
 		}
 		lex.advance()
-		escapeChar = false
 	}
 
 	lex.advance()
 
-	return NewToken(StringTT, str)
+	if parts == nil {
+		return []*Token{NewToken(StringTT, str)}, nil
+	}
+
+	tokens := []*Token{NewToken(InterpStartTT, "\"")}
+	tokens = append(tokens, parts...)
+	tokens = append(tokens, NewToken(StringTT, str), NewToken(InterpEndTT, "\""))
+
+	return tokens, nil
 }
 
 func (lex *Lexer) Tokenize() ([]*Token, error) {
+	tokens, err := lex.tokenize(false)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens = append(tokens, NewToken(EOFTT, ""))
+	return tokens, nil
+}
+
+// tokenize reads tokens until the end of the input or, when insideInterp is
+// set, until the '}' closing a '${' string interpolation
+func (lex *Lexer) tokenize(insideInterp bool) ([]*Token, error) {
 	tokens := make([]*Token, 0)
+	braceDepth := 0
 
 	for lex.currentChar != "" {
-		if lex.isSkippable(lex.currentChar) {
+		if insideInterp && lex.currentChar == "}" && braceDepth == 0 {
+			break
+		} else if lex.isSkippable(lex.currentChar) {
 			lex.advance()
 		} else if lex.isDigit(lex.currentChar) {
 			tokens = append(tokens, lex.makeNumber())
 		} else if lex.isAlpha(lex.currentChar) {
 			tokens = append(tokens, lex.makeIdentifier())
 		} else if lex.currentChar == "\"" {
-			tokens = append(tokens, lex.makeString())
-		} else if lex.currentChar == "+" {
-			tokens = append(tokens, NewToken(PlusTT, lex.currentChar))
-			lex.advance()
-		} else if lex.currentChar == "-" {
+			strTokens, err := lex.makeString()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, strTokens...)
+		} else if lex.currentChar == "+" { // creates '+', '+=' or '++'
+			tokens = append(tokens, lex.makePlus())
+		} else if lex.currentChar == "-" { // creates '-', '-=', '--' or '->'
 			tokens = append(tokens, lex.makeMinusOrArrow())
-		} else if lex.currentChar == "*" {
-			tokens = append(tokens, NewToken(MultiplyTT, lex.currentChar))
-			lex.advance()
-		} else if lex.currentChar == "/" {
-			tokens = append(tokens, NewToken(DivideTT, lex.currentChar))
-			lex.advance()
-		} else if lex.currentChar == "%" {
-			tokens = append(tokens, NewToken(ModTT, lex.currentChar))
-			lex.advance()
-		} else if lex.currentChar == "^" {
-			tokens = append(tokens, NewToken(PowerTT, lex.currentChar))
-			lex.advance()
+		} else if lex.currentChar == "*" { // creates '*' or '*='
+			tokens = append(tokens, lex.makeOperator(MultiplyTT, MultiplyEqualsTT))
+		} else if lex.currentChar == "/" { // creates '/', '/=' or '//'
+			tokens = append(tokens, lex.makeSlash())
+		} else if lex.currentChar == "%" { // creates '%' or '%='
+			tokens = append(tokens, lex.makeOperator(ModTT, ModEqualsTT))
+		} else if lex.currentChar == "^" { // creates '^' or '^='
+			tokens = append(tokens, lex.makeOperator(PowerTT, PowerEqualsTT))
 		} else if lex.currentChar == "(" {
 			tokens = append(tokens, NewToken(OpenParenTT, lex.currentChar))
 			lex.advance()
@@ -233,6 +404,14 @@ func (lex *Lexer) Tokenize() ([]*Token, error) {
 		} else if lex.currentChar == "]" {
 			tokens = append(tokens, NewToken(CloseBracketTT, lex.currentChar))
 			lex.advance()
+		} else if lex.currentChar == "{" {
+			tokens = append(tokens, NewToken(OpenBraceTT, lex.currentChar))
+			braceDepth++
+			lex.advance()
+		} else if lex.currentChar == "}" {
+			tokens = append(tokens, NewToken(CloseBraceTT, lex.currentChar))
+			braceDepth--
+			lex.advance()
 		} else if lex.currentChar == "!" {
 			neToken, err := lex.makeNotEquals()
 			if err != nil {
@@ -241,13 +420,32 @@ func (lex *Lexer) Tokenize() ([]*Token, error) {
 			tokens = append(tokens, neToken)
 		} else if lex.currentChar == "=" { // creates '=' or '=='
 			tokens = append(tokens, lex.makeEquals())
-		} else if lex.currentChar == "<" { // creates '<' or '<='
+		} else if lex.currentChar == "<" { // creates '<', '<=' or '<<'
 			tokens = append(tokens, lex.makeLessThan())
-		} else if lex.currentChar == ">" { // creates '>' or '>='
+		} else if lex.currentChar == ">" { // creates '>', '>=' or '>>'
 			tokens = append(tokens, lex.makeGreaterThan())
+		} else if lex.currentChar == "?" { // creates '?', '?.' or '??'
+			questionToken, err := lex.makeQuestion()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, questionToken)
+		} else if lex.currentChar == "|" { // creates '|' or '|>'
+			tokens = append(tokens, lex.makeBarOrPipe())
+		} else if lex.currentChar == "&" {
+			tokens = append(tokens, NewToken(AmpersandTT, lex.currentChar))
+			lex.advance()
+		} else if lex.currentChar == "~" {
+			tokens = append(tokens, NewToken(TildeTT, lex.currentChar))
+			lex.advance()
 		} else if lex.currentChar == "," {
 			tokens = append(tokens, NewToken(CommaTT, lex.currentChar))
 			lex.advance()
+		} else if lex.currentChar == "." { // creates '.', '..', '..=' or '...'
+			tokens = append(tokens, lex.makeDot())
+		} else if lex.currentChar == ":" {
+			tokens = append(tokens, NewToken(ColonTT, lex.currentChar))
+			lex.advance()
 		} else {
 			cc := lex.currentChar
 			lex.advance()
@@ -256,6 +454,5 @@ func (lex *Lexer) Tokenize() ([]*Token, error) {
 
 	}
 
-	tokens = append(tokens, NewToken(EOFTT, ""))
 	return tokens, nil
 }
@@ -5,6 +5,8 @@ type TokenType string
 const (
 	IntTT               TokenType = "Int"
 	FloatTT             TokenType = "Float"
+	DecimalTT           TokenType = "Decimal"
+	ImaginaryTT         TokenType = "Imaginary"
 	IdentifierTT        TokenType = "Identifier"
 	KeywordTT           TokenType = "Keyword"
 	PlusTT              TokenType = "Plus"
@@ -14,19 +16,48 @@ const (
 	ModTT               TokenType = "Mod"
 	PowerTT             TokenType = "Power"
 	EqualsTT            TokenType = "Equals"
+	PlusEqualsTT        TokenType = "PlusEquals"
+	MinusEqualsTT       TokenType = "MinusEquals"
+	MultiplyEqualsTT    TokenType = "MultiplyEquals"
+	DivideEqualsTT      TokenType = "DivideEquals"
+	ModEqualsTT         TokenType = "ModEquals"
+	PowerEqualsTT       TokenType = "PowerEquals"
+	DoublePlusTT        TokenType = "DoublePlus"
+	DoubleMinusTT       TokenType = "DoubleMinus"
 	OpenParenTT         TokenType = "OpenParen"
 	CloseParenTT        TokenType = "CloseParen"
 	OpenBracketTT       TokenType = "OpenBracket"
 	CloseBracketTT      TokenType = "CloseBracket"
+	OpenBraceTT         TokenType = "OpenBrace"
+	CloseBraceTT        TokenType = "CloseBrace"
 	DoubleEqualsTT      TokenType = "DoubleEquals"
 	NotEqualsTT         TokenType = "NotEquals"
 	LessThanTT          TokenType = "LessThan"
 	GreaterThanTT       TokenType = "GreaterThan"
 	LessThanEqualsTT    TokenType = "LessThanEquals"
 	GreaterThanEqualsTT TokenType = "GreaterThanEquals"
+	DoubleGreaterThanTT TokenType = "DoubleGreaterThan"
+	DoubleLessThanTT    TokenType = "DoubleLessThan"
+	DoubleSlashTT       TokenType = "DoubleSlash"
+	AmpersandTT         TokenType = "Ampersand"
+	BarTT               TokenType = "Bar"
+	TildeTT             TokenType = "Tilde"
+	PipeTT              TokenType = "Pipe"
+	QuestionTT          TokenType = "Question"
+	QuestionDotTT       TokenType = "QuestionDot"
+	DoubleQuestionTT    TokenType = "DoubleQuestion"
 	CommaTT             TokenType = "Comma"
+	ColonTT             TokenType = "Colon"
+	DotTT               TokenType = "Dot"
+	EllipsisTT          TokenType = "Ellipsis"
+	DoubleDotTT         TokenType = "DoubleDot"
+	DoubleDotEqualsTT   TokenType = "DoubleDotEquals"
 	ArrowTT             TokenType = "Arrow"
 	StringTT            TokenType = "String"
+	InterpStartTT       TokenType = "InterpStart"
+	InterpOpenTT        TokenType = "InterpOpen"
+	InterpCloseTT       TokenType = "InterpClose"
+	InterpEndTT         TokenType = "InterpEnd"
 	EOFTT               TokenType = "EOF"
 )
 
@@ -46,4 +77,4 @@ func (t *Token) Matches(tt TokenType, v string) bool {
 	return t.Type == tt && t.Value == v
 }
 
-var KEYWORDS = []string{"var", "and", "or", "not", "if", "then", "elif", "else", "for", "to", "step", "while", "fun"}
+var KEYWORDS = []string{"var", "and", "or", "not", "if", "then", "elif", "else", "for", "to", "step", "while", "fun", "struct", "class", "extends", "trait", "enum", "match", "try", "catch", "finally", "throw", "defer", "in", "yield", "collect", "xor"}
@@ -1,22 +1,60 @@
 package parser
 
-import "go-interpreter/lexer"
+import (
+	"go-interpreter/lexer"
+	"go-interpreter/units"
+)
 
 type NodeType string
 
 const (
-	NumberNT    NodeType = "Number"
-	UnOpNT      NodeType = "UnOp"
-	BinOpNt     NodeType = "BinOp"
-	VarAccessNT NodeType = "VarAccess"
-	VarAssignNT NodeType = "VarAssign"
-	IfNT        NodeType = "If"
-	ForNT       NodeType = "For"
-	WhileNT     NodeType = "While"
-	FuncDefNT   NodeType = "FunDef"
-	CallNT      NodeType = "Call"
-	StringNT    NodeType = "String"
-	ListNT      NodeType = "List"
+	NumberNT         NodeType = "Number"
+	UnOpNT           NodeType = "UnOp"
+	BinOpNt          NodeType = "BinOp"
+	VarAccessNT      NodeType = "VarAccess"
+	VarAssignNT      NodeType = "VarAssign"
+	IfNT             NodeType = "If"
+	ForNT            NodeType = "For"
+	WhileNT          NodeType = "While"
+	FuncDefNT        NodeType = "FunDef"
+	CallNT           NodeType = "Call"
+	StringNT         NodeType = "String"
+	ListNT           NodeType = "List"
+	InterpNT         NodeType = "Interp"
+	IndexNT          NodeType = "Index"
+	SliceNT          NodeType = "Slice"
+	IndexAssignNT    NodeType = "IndexAssign"
+	MapNT            NodeType = "Map"
+	StructDefNT      NodeType = "StructDef"
+	MemberAccessNT   NodeType = "MemberAccess"
+	MemberAssignNT   NodeType = "MemberAssign"
+	ClassDefNT       NodeType = "ClassDef"
+	TraitDefNT       NodeType = "TraitDef"
+	EnumDefNT        NodeType = "EnumDef"
+	MatchNT          NodeType = "Match"
+	DestructureNT    NodeType = "Destructure"
+	KeywordArgNT     NodeType = "KeywordArg"
+	SpreadNT         NodeType = "Spread"
+	ThrowNT          NodeType = "Throw"
+	TryNT            NodeType = "Try"
+	DeferNT          NodeType = "Defer"
+	ForInNT          NodeType = "ForIn"
+	YieldNT          NodeType = "Yield"
+	ListCompNT       NodeType = "ListComp"
+	MapCompNT        NodeType = "MapComp"
+	RangeNT          NodeType = "Range"
+	OptionalChainNT  NodeType = "OptionalChain"
+	CompoundAssignNT NodeType = "CompoundAssign"
+	IncrementNT      NodeType = "Increment"
+	QuantityNT       NodeType = "Quantity"
+	ConvertNT        NodeType = "Convert"
+
+	BindingPatternNT     NodeType = "BindingPattern"
+	ConstructorPatternNT NodeType = "ConstructorPattern"
+	LiteralPatternNT     NodeType = "LiteralPattern"
+	TypePatternNT        NodeType = "TypePattern"
+	ListPatternNT        NodeType = "ListPattern"
+	MapPatternNT         NodeType = "MapPattern"
 )
 
 type AstNode interface {
@@ -150,6 +188,7 @@ type ForNode struct {
 	EndValue   AstNode
 	StepValue  AstNode
 	Body       AstNode
+	Collect    bool // return the list of the body results
 }
 
 func NewForNode(vn *lexer.Token, s, e, st, b AstNode) *ForNode {
@@ -167,12 +206,36 @@ func (n *ForNode) GetType() NodeType {
 	return n.Type
 }
 
+// ForInNode
+
+type ForInNode struct {
+	Type     NodeType
+	Pattern  AstNode
+	Iterable AstNode
+	Body     AstNode
+	Collect  bool // return the list of the body results
+}
+
+func NewForInNode(p, i, b AstNode) *ForInNode {
+	return &ForInNode{
+		Type:     ForInNT,
+		Pattern:  p,
+		Iterable: i,
+		Body:     b,
+	}
+}
+
+func (n *ForInNode) GetType() NodeType {
+	return n.Type
+}
+
 // WhileNode
 
 type WhileNode struct {
 	Type      NodeType
 	Condition AstNode
 	Body      AstNode
+	Collect   bool // return the list of the body results
 }
 
 func NewWhileNode(c, b AstNode) *WhileNode {
@@ -190,18 +253,24 @@ func (n *WhileNode) GetType() NodeType {
 // FuncDefNode
 
 type FuncDefNode struct {
-	Type    NodeType
-	VarName *lexer.Token
-	Args    []*lexer.Token
-	Body    AstNode
+	Type        NodeType
+	VarName     *lexer.Token
+	Args        []*lexer.Token
+	Defaults    []AstNode    // default value of each arg, nil if it has none
+	RestArg     *lexer.Token // collects the extra positional args, if any
+	Body        AstNode
+	IsGenerator bool // the body contains 'yield'
 }
 
-func NewFuncDefNode(v *lexer.Token, a []*lexer.Token, b AstNode) *FuncDefNode {
+func NewFuncDefNode(v *lexer.Token, a []*lexer.Token, d []AstNode, r *lexer.Token, b AstNode, g bool) *FuncDefNode {
 	return &FuncDefNode{
-		Type:    FuncDefNT,
-		VarName: v,
-		Args:    a,
-		Body:    b,
+		Type:        FuncDefNT,
+		VarName:     v,
+		Args:        a,
+		Defaults:    d,
+		RestArg:     r,
+		Body:        b,
+		IsGenerator: g,
 	}
 }
 
@@ -212,9 +281,10 @@ func (n *FuncDefNode) GetType() NodeType {
 // CallNode
 
 type CallNode struct {
-	Type NodeType
-	Node AstNode
-	Args []AstNode
+	Type     NodeType
+	Node     AstNode
+	Args     []AstNode
+	Optional bool // '?.(', evaluates to null when Node is null
 }
 
 func NewCallNode(n AstNode, a []AstNode) *CallNode {
@@ -264,3 +334,716 @@ func NewListNode(els []AstNode) *ListNode {
 func (n *ListNode) GetType() NodeType {
 	return n.Type
 }
+
+// InterpNode
+
+type InterpNode struct {
+	Type  NodeType
+	Parts []AstNode
+}
+
+func NewInterpNode(p []AstNode) *InterpNode {
+	return &InterpNode{
+		Type:  InterpNT,
+		Parts: p,
+	}
+}
+
+func (n *InterpNode) GetType() NodeType {
+	return n.Type
+}
+
+// IndexNode
+
+type IndexNode struct {
+	Type     NodeType
+	Node     AstNode
+	Index    AstNode
+	Optional bool // evaluates to null when Node is null
+}
+
+func NewIndexNode(n, i AstNode) *IndexNode {
+	return &IndexNode{
+		Type:  IndexNT,
+		Node:  n,
+		Index: i,
+	}
+}
+
+func (n *IndexNode) GetType() NodeType {
+	return n.Type
+}
+
+// SliceNode
+
+type SliceNode struct {
+	Type     NodeType
+	Node     AstNode
+	Start    AstNode
+	End      AstNode
+	Step     AstNode
+	Optional bool // evaluates to null when Node is null
+}
+
+func NewSliceNode(n, s, e, st AstNode) *SliceNode {
+	return &SliceNode{
+		Type:  SliceNT,
+		Node:  n,
+		Start: s,
+		End:   e,
+		Step:  st,
+	}
+}
+
+func (n *SliceNode) GetType() NodeType {
+	return n.Type
+}
+
+// IndexAssignNode
+
+type IndexAssignNode struct {
+	Type  NodeType
+	Node  AstNode
+	Index AstNode
+	Value AstNode
+}
+
+func NewIndexAssignNode(n, i, v AstNode) *IndexAssignNode {
+	return &IndexAssignNode{
+		Type:  IndexAssignNT,
+		Node:  n,
+		Index: i,
+		Value: v,
+	}
+}
+
+func (n *IndexAssignNode) GetType() NodeType {
+	return n.Type
+}
+
+// MapNode
+
+type MapNode struct {
+	Type   NodeType
+	Keys   []AstNode
+	Values []AstNode
+}
+
+func NewMapNode(k, v []AstNode) *MapNode {
+	return &MapNode{
+		Type:   MapNT,
+		Keys:   k,
+		Values: v,
+	}
+}
+
+func (n *MapNode) GetType() NodeType {
+	return n.Type
+}
+
+// StructDefNode
+
+type StructDefNode struct {
+	Type    NodeType
+	VarName *lexer.Token
+	Fields  []*lexer.Token
+	Traits  []*lexer.Token
+	Methods []*FuncDefNode
+}
+
+func NewStructDefNode(v *lexer.Token, f, t []*lexer.Token, m []*FuncDefNode) *StructDefNode {
+	return &StructDefNode{
+		Type:    StructDefNT,
+		VarName: v,
+		Fields:  f,
+		Traits:  t,
+		Methods: m,
+	}
+}
+
+func (n *StructDefNode) GetType() NodeType {
+	return n.Type
+}
+
+// MemberAccessNode
+
+type MemberAccessNode struct {
+	Type     NodeType
+	Node     AstNode
+	Member   *lexer.Token
+	Optional bool // evaluates to null when Node is null
+}
+
+func NewMemberAccessNode(n AstNode, m *lexer.Token) *MemberAccessNode {
+	return &MemberAccessNode{
+		Type:   MemberAccessNT,
+		Node:   n,
+		Member: m,
+	}
+}
+
+func (n *MemberAccessNode) GetType() NodeType {
+	return n.Type
+}
+
+// MemberAssignNode
+
+type MemberAssignNode struct {
+	Type   NodeType
+	Node   AstNode
+	Member *lexer.Token
+	Value  AstNode
+}
+
+func NewMemberAssignNode(n AstNode, m *lexer.Token, v AstNode) *MemberAssignNode {
+	return &MemberAssignNode{
+		Type:   MemberAssignNT,
+		Node:   n,
+		Member: m,
+		Value:  v,
+	}
+}
+
+func (n *MemberAssignNode) GetType() NodeType {
+	return n.Type
+}
+
+// ClassDefNode
+
+type ClassDefNode struct {
+	Type    NodeType
+	VarName *lexer.Token
+	Parent  *lexer.Token
+	Traits  []*lexer.Token
+	Methods []*FuncDefNode
+}
+
+func NewClassDefNode(v, p *lexer.Token, t []*lexer.Token, m []*FuncDefNode) *ClassDefNode {
+	return &ClassDefNode{
+		Type:    ClassDefNT,
+		VarName: v,
+		Parent:  p,
+		Traits:  t,
+		Methods: m,
+	}
+}
+
+func (n *ClassDefNode) GetType() NodeType {
+	return n.Type
+}
+
+// TraitDefNode
+
+type TraitMethod struct {
+	Name *lexer.Token
+	Args []*lexer.Token
+}
+
+type TraitDefNode struct {
+	Type    NodeType
+	VarName *lexer.Token
+	Methods []*TraitMethod
+}
+
+func NewTraitDefNode(v *lexer.Token, m []*TraitMethod) *TraitDefNode {
+	return &TraitDefNode{
+		Type:    TraitDefNT,
+		VarName: v,
+		Methods: m,
+	}
+}
+
+func (n *TraitDefNode) GetType() NodeType {
+	return n.Type
+}
+
+// EnumDefNode
+
+type EnumVariant struct {
+	Name   *lexer.Token
+	Fields []*lexer.Token // nil for variants without payload
+}
+
+type EnumDefNode struct {
+	Type     NodeType
+	VarName  *lexer.Token
+	Variants []*EnumVariant
+}
+
+func NewEnumDefNode(v *lexer.Token, vs []*EnumVariant) *EnumDefNode {
+	return &EnumDefNode{
+		Type:     EnumDefNT,
+		VarName:  v,
+		Variants: vs,
+	}
+}
+
+func (n *EnumDefNode) GetType() NodeType {
+	return n.Type
+}
+
+// MatchNode
+
+type MatchArm struct {
+	Pattern AstNode
+	Guard   AstNode
+	Body    AstNode
+}
+
+type MatchNode struct {
+	Type    NodeType
+	Subject AstNode
+	Arms    []*MatchArm
+}
+
+func NewMatchNode(s AstNode, a []*MatchArm) *MatchNode {
+	return &MatchNode{
+		Type:    MatchNT,
+		Subject: s,
+		Arms:    a,
+	}
+}
+
+func (n *MatchNode) GetType() NodeType {
+	return n.Type
+}
+
+// BindingPatternNode
+
+type BindingPatternNode struct {
+	Type    NodeType
+	VarName *lexer.Token
+}
+
+func NewBindingPatternNode(v *lexer.Token) *BindingPatternNode {
+	return &BindingPatternNode{
+		Type:    BindingPatternNT,
+		VarName: v,
+	}
+}
+
+func (n *BindingPatternNode) GetType() NodeType {
+	return n.Type
+}
+
+// ConstructorPatternNode
+
+type ConstructorPatternNode struct {
+	Type NodeType
+	Path []*lexer.Token
+	Args []AstNode // nil when the pattern has no parentheses
+}
+
+func NewConstructorPatternNode(p []*lexer.Token, a []AstNode) *ConstructorPatternNode {
+	return &ConstructorPatternNode{
+		Type: ConstructorPatternNT,
+		Path: p,
+		Args: a,
+	}
+}
+
+func (n *ConstructorPatternNode) GetType() NodeType {
+	return n.Type
+}
+
+// LiteralPatternNode
+
+type LiteralPatternNode struct {
+	Type  NodeType
+	Value AstNode
+}
+
+func NewLiteralPatternNode(v AstNode) *LiteralPatternNode {
+	return &LiteralPatternNode{
+		Type:  LiteralPatternNT,
+		Value: v,
+	}
+}
+
+func (n *LiteralPatternNode) GetType() NodeType {
+	return n.Type
+}
+
+// TypePatternNode
+
+type TypePatternNode struct {
+	Type     NodeType
+	VarName  *lexer.Token
+	TypeName []*lexer.Token
+}
+
+func NewTypePatternNode(v *lexer.Token, t []*lexer.Token) *TypePatternNode {
+	return &TypePatternNode{
+		Type:     TypePatternNT,
+		VarName:  v,
+		TypeName: t,
+	}
+}
+
+func (n *TypePatternNode) GetType() NodeType {
+	return n.Type
+}
+
+// ListPatternNode
+
+type ListPatternNode struct {
+	Type   NodeType
+	Before []AstNode
+	Rest   *lexer.Token // nil when the pattern has no '...rest' element
+	After  []AstNode
+}
+
+func NewListPatternNode(b []AstNode, r *lexer.Token, a []AstNode) *ListPatternNode {
+	return &ListPatternNode{
+		Type:   ListPatternNT,
+		Before: b,
+		Rest:   r,
+		After:  a,
+	}
+}
+
+func (n *ListPatternNode) GetType() NodeType {
+	return n.Type
+}
+
+// MapPatternNode
+
+type MapPatternNode struct {
+	Type   NodeType
+	Keys   []AstNode
+	Values []AstNode
+}
+
+func NewMapPatternNode(k, v []AstNode) *MapPatternNode {
+	return &MapPatternNode{
+		Type:   MapPatternNT,
+		Keys:   k,
+		Values: v,
+	}
+}
+
+func (n *MapPatternNode) GetType() NodeType {
+	return n.Type
+}
+
+// DestructureNode
+
+type DestructureNode struct {
+	Type    NodeType
+	Pattern AstNode
+	Value   AstNode
+}
+
+func NewDestructureNode(p, v AstNode) *DestructureNode {
+	return &DestructureNode{
+		Type:    DestructureNT,
+		Pattern: p,
+		Value:   v,
+	}
+}
+
+func (n *DestructureNode) GetType() NodeType {
+	return n.Type
+}
+
+// KeywordArgNode
+
+type KeywordArgNode struct {
+	Type  NodeType
+	Name  *lexer.Token
+	Value AstNode
+}
+
+func NewKeywordArgNode(n *lexer.Token, v AstNode) *KeywordArgNode {
+	return &KeywordArgNode{
+		Type:  KeywordArgNT,
+		Name:  n,
+		Value: v,
+	}
+}
+
+func (n *KeywordArgNode) GetType() NodeType {
+	return n.Type
+}
+
+// SpreadNode
+
+type SpreadNode struct {
+	Type NodeType
+	Node AstNode
+}
+
+func NewSpreadNode(n AstNode) *SpreadNode {
+	return &SpreadNode{
+		Type: SpreadNT,
+		Node: n,
+	}
+}
+
+func (n *SpreadNode) GetType() NodeType {
+	return n.Type
+}
+
+// ThrowNode
+
+type ThrowNode struct {
+	Type NodeType
+	Node AstNode
+}
+
+func NewThrowNode(n AstNode) *ThrowNode {
+	return &ThrowNode{
+		Type: ThrowNT,
+		Node: n,
+	}
+}
+
+func (n *ThrowNode) GetType() NodeType {
+	return n.Type
+}
+
+// TryNode
+
+type TryNode struct {
+	Type     NodeType
+	Body     AstNode
+	CatchVar *lexer.Token // nil when there is no catch clause
+	Catch    AstNode
+	Finally  AstNode // nil when there is no finally clause
+}
+
+func NewTryNode(b AstNode, cv *lexer.Token, c, f AstNode) *TryNode {
+	return &TryNode{
+		Type:     TryNT,
+		Body:     b,
+		CatchVar: cv,
+		Catch:    c,
+		Finally:  f,
+	}
+}
+
+func (n *TryNode) GetType() NodeType {
+	return n.Type
+}
+
+// DeferNode
+
+type DeferNode struct {
+	Type NodeType
+	Node AstNode
+}
+
+func NewDeferNode(n AstNode) *DeferNode {
+	return &DeferNode{
+		Type: DeferNT,
+		Node: n,
+	}
+}
+
+func (n *DeferNode) GetType() NodeType {
+	return n.Type
+}
+
+// YieldNode
+
+type YieldNode struct {
+	Type NodeType
+	Node AstNode
+}
+
+func NewYieldNode(n AstNode) *YieldNode {
+	return &YieldNode{
+		Type: YieldNT,
+		Node: n,
+	}
+}
+
+func (n *YieldNode) GetType() NodeType {
+	return n.Type
+}
+
+// CompClause is a 'for pattern in iterable' or an 'if condition' clause of
+// a comprehension, Condition is only set for 'if' clauses
+
+type CompClause struct {
+	Pattern   AstNode
+	Iterable  AstNode
+	Condition AstNode
+}
+
+// ListCompNode
+
+type ListCompNode struct {
+	Type    NodeType
+	Element AstNode
+	Clauses []*CompClause
+}
+
+func NewListCompNode(e AstNode, c []*CompClause) *ListCompNode {
+	return &ListCompNode{
+		Type:    ListCompNT,
+		Element: e,
+		Clauses: c,
+	}
+}
+
+func (n *ListCompNode) GetType() NodeType {
+	return n.Type
+}
+
+// MapCompNode
+
+type MapCompNode struct {
+	Type    NodeType
+	Key     AstNode
+	Value   AstNode
+	Clauses []*CompClause
+}
+
+func NewMapCompNode(k, v AstNode, c []*CompClause) *MapCompNode {
+	return &MapCompNode{
+		Type:    MapCompNT,
+		Key:     k,
+		Value:   v,
+		Clauses: c,
+	}
+}
+
+func (n *MapCompNode) GetType() NodeType {
+	return n.Type
+}
+
+// RangeNode
+
+type RangeNode struct {
+	Type      NodeType
+	Start     AstNode
+	End       AstNode
+	Step      AstNode // nil when omitted
+	Inclusive bool
+}
+
+func NewRangeNode(s, e, st AstNode, i bool) *RangeNode {
+	return &RangeNode{
+		Type:      RangeNT,
+		Start:     s,
+		End:       e,
+		Step:      st,
+		Inclusive: i,
+	}
+}
+
+func (n *RangeNode) GetType() NodeType {
+	return n.Type
+}
+
+// OptionalChainNode
+
+// OptionalChainNode wraps a chain of calls, indexes and member accesses
+// containing optional links, it evaluates to null when one of them
+// short-circuits
+type OptionalChainNode struct {
+	Type NodeType
+	Node AstNode
+}
+
+func NewOptionalChainNode(n AstNode) *OptionalChainNode {
+	return &OptionalChainNode{
+		Type: OptionalChainNT,
+		Node: n,
+	}
+}
+
+func (n *OptionalChainNode) GetType() NodeType {
+	return n.Type
+}
+
+// CompoundAssignNode
+
+// CompoundAssignNode is 'target op= value', where target is a variable,
+// an index or a member access
+type CompoundAssignNode struct {
+	Type     NodeType
+	Target   AstNode
+	Operator *lexer.Token
+	Value    AstNode
+}
+
+func NewCompoundAssignNode(t AstNode, o *lexer.Token, v AstNode) *CompoundAssignNode {
+	return &CompoundAssignNode{
+		Type:     CompoundAssignNT,
+		Target:   t,
+		Operator: o,
+		Value:    v,
+	}
+}
+
+func (n *CompoundAssignNode) GetType() NodeType {
+	return n.Type
+}
+
+// IncrementNode
+
+// IncrementNode is '++target', '--target', 'target++' or 'target--', the
+// prefix forms evaluate to the updated value, the postfix ones to the old one
+type IncrementNode struct {
+	Type     NodeType
+	Target   AstNode
+	Operator *lexer.Token
+	Prefix   bool
+}
+
+func NewIncrementNode(t AstNode, o *lexer.Token, p bool) *IncrementNode {
+	return &IncrementNode{
+		Type:     IncrementNT,
+		Target:   t,
+		Operator: o,
+		Prefix:   p,
+	}
+}
+
+func (n *IncrementNode) GetType() NodeType {
+	return n.Type
+}
+
+// QuantityNode
+
+// QuantityNode is a number literal followed by a unit, as in '9.81 m/s^2'
+type QuantityNode struct {
+	Type NodeType
+	Node AstNode
+	Unit units.Unit
+}
+
+func NewQuantityNode(n AstNode, u units.Unit) *QuantityNode {
+	return &QuantityNode{
+		Type: QuantityNT,
+		Node: n,
+		Unit: u,
+	}
+}
+
+func (n *QuantityNode) GetType() NodeType {
+	return n.Type
+}
+
+// ConvertNode
+
+// ConvertNode is the unit conversion 'x to unit'
+type ConvertNode struct {
+	Type NodeType
+	Node AstNode
+	Unit units.Unit
+}
+
+func NewConvertNode(n AstNode, u units.Unit) *ConvertNode {
+	return &ConvertNode{
+		Type: ConvertNT,
+		Node: n,
+		Unit: u,
+	}
+}
+
+func (n *ConvertNode) GetType() NodeType {
+	return n.Type
+}
@@ -1,37 +1,99 @@
 package parser
 
 import (
+	"fmt"
 	"go-interpreter/lexer"
+	"go-interpreter/units"
 	"go-interpreter/utils"
 	"slices"
+	"strconv"
 )
 
 // expr      : KEYWORD:var IDENTIFIER EQ expr
-//           : comp ((KEYWORD:and|KEYWORD:or) comp)*
+//           : KEYWORD:var pattern EQ expr (when pattern is a list or map pattern)
+//           : call EQ expr (when call ends with an index or a member access)
+//           : call (PLUSEQ|MINUSEQ|MULEQ|DIVEQ|MODEQ|POWEQ) expr (when call is a variable, an index or a member access)
+//           : coalesce-expr
+
+// coalesce-expr: logic-expr (DOUBLEQUESTION logic-expr)*
+
+// logic-expr: comp ((KEYWORD:and|KEYWORD:or) comp)*
 
 // comp      : KEYWORD:not comp
-//           : mathExpr ((EE|NE|LT|LTE|GT|GTE) mathExpr)*
+//           : pipe-expr ((EE|NE|LT|LTE|GT|GTE|KEYWORD:in) pipe-expr)*
+
+// pipe-expr : convert-expr (PIPE convert-expr)*
+
+// convert-expr: range-expr (KEYWORD:to unit)*
+
+// range-expr: bit-or-expr ((DOTDOT|DOTDOTEQ) bit-or-expr (KEYWORD:step bit-or-expr)?)?
+
+// bit-or-expr: bit-xor-expr (BAR bit-xor-expr)*
+
+// bit-xor-expr: bit-and-expr (KEYWORD:xor bit-and-expr)*
+
+// bit-and-expr: shift-expr (AMPERSAND shift-expr)*
+
+// shift-expr: math-expr ((DOUBLELT|DOUBLEGT) math-expr)*
 
 // math-expr : term ((PLUS|MINUS) term)*
 
-// term      : factor ((MUL|DIV|MOD) factor)*
+// term      : factor ((MUL|DIV|DOUBLESLASH|MOD) factor)*
 
-// factor    : (PLUS|MINUS) factor
+// factor    : (PLUS|MINUS|TILDE) factor
+//           : (DOUBLEPLUS|DOUBLEMINUS) postfix-expr (when postfix-expr is a variable, an index or a member access)
 //           : powerExpr
 
-// power-expr: call (POW factor)*
+// power-expr: postfix-expr (POW factor)*
+
+// postfix-expr: call (DOUBLEPLUS|DOUBLEMINUS)? (when call is a variable, an index or a member access)
+
+// call      : atom (QUESTIONDOT? OpenParen (call-arg (COMMA call-arg)*)? RightParen
+//           :       | (QUESTION|QUESTIONDOT)? index | (DOT|QUESTIONDOT) IDENTIFIER)*
+
+// call-arg  : IDENTIFIER EQ expr
+//           : ELLIPSIS expr
+//           : expr
 
-// call      : atom (OpenParen (expr (COMMA expr)*)? RightParen)?
+// index     : OpenBracket expr CloseBracket
+//           : OpenBracket expr? COLON expr? (COLON expr?)? CloseBracket
 
-// atom      : INT|FLOAT|STRING|IDENTIFIER
+// unit      : unit-term ((MUL|DIV) unit-term)*
+//             (always units, even when a variable has the same name: '(10 m) / s' divides by s)
+
+// unit-term : IDENTIFIER (POW MINUS? INT)? (when IDENTIFIER is a known unit)
+
+// atom      : (INT|FLOAT|DECIMAL|IMAGINARY) unit?
+//           : STRING|IDENTIFIER
 //	         : OpenParen expr CloseParen
+//           : interp-expr
 //           : list
+//           : map
 //           : if-expr
 //           : for-expr
 //           : while-expr
+//           : collect-expr
 //           : func-def
+//           : struct-def
+//           : class-def
+//           : trait-def
+//           : enum-def
+//           : match-expr
+//           : try-expr
+//           : KEYWORD:throw expr
+//           : KEYWORD:defer expr
+//           : KEYWORD:yield expr
+
+// interp-expr: InterpStart STRING (InterpOpen expr InterpClose STRING)* InterpEnd
 
 // list-expr : OpenBracket (expr, (COMMA expr)*)? CloseBracket
+//           : OpenBracket expr comp-clause+ CloseBracket
+
+// map-expr  : OpenBrace (expr COLON expr (COMMA expr COLON expr)*)? CloseBrace
+//           : OpenBrace expr COLON expr comp-clause+ CloseBrace
+
+// comp-clause: KEYWORD:for pattern KEYWORD:in expr
+//            : KEYWORD:if expr
 
 // if-expr   : KEYOWRD:if expr KEYWORD:then expr
 //           : (KEYWORD:elif expr KEYWORD:then expr)*
@@ -39,17 +101,64 @@ import (
 
 // for-expr  : KEYWORD:for IDENTIFIER EQ expr KEYWORD:to expr
 //           : (KEYWORD:step expr)? KEYWORD:then expr
+//           : KEYWORD:for pattern KEYWORD:in expr KEYWORD:then expr
 
 // while-expr: KEYWORD:while expr KEYWORD:then expr
 
+// collect-expr: KEYWORD:collect (for-expr|while-expr)
+
 // func-def  : KEYWORD:fun IDENTIFIER?
-//           : OpenParen (IDENTIFIER (COMMA IDENTIFIER)*)? CloseParen
+//           : OpenParen (param (COMMA param)*)? (COMMA? ELLIPSIS IDENTIFIER)? CloseParen
 //           : ARROW expr
 
+// param     : IDENTIFIER (EQ expr)?
+
+// struct-def: KEYWORD:struct IDENTIFIER
+//           : OpenParen (IDENTIFIER (COMMA IDENTIFIER)*)? CloseParen
+//           : implements? (OpenBrace func-def* CloseBrace)?
+
+// class-def : KEYWORD:class IDENTIFIER (KEYWORD:extends IDENTIFIER)?
+//           : implements? OpenBrace func-def* CloseBrace
+
+// implements: IDENTIFIER:implements IDENTIFIER (COMMA IDENTIFIER)*
+
+// trait-def : KEYWORD:trait IDENTIFIER OpenBrace
+//           : (IDENTIFIER OpenParen (IDENTIFIER (COMMA IDENTIFIER)*)? CloseParen COMMA?)*
+//           : CloseBrace
+
+// enum-def  : KEYWORD:enum IDENTIFIER OpenBrace
+//           : (IDENTIFIER (OpenParen (IDENTIFIER (COMMA IDENTIFIER)*)? CloseParen)? COMMA?)*
+//           : CloseBrace
+
+// try-expr  : KEYWORD:try expr
+//           : (KEYWORD:catch IDENTIFIER KEYWORD:then expr)?
+//           : (KEYWORD:finally expr)?
+
+// match-expr: KEYWORD:match expr OpenBrace (match-arm COMMA?)* CloseBrace
+
+// match-arm : pattern (KEYWORD:if expr)? ARROW expr
+
+// pattern   : MINUS? (INT|FLOAT) | STRING | IDENTIFIER:(true|false|null)
+//           : IDENTIFIER (COLON IDENTIFIER (DOT IDENTIFIER)*)?
+//           : IDENTIFIER (DOT IDENTIFIER)* (OpenParen (pattern (COMMA pattern)*)? CloseParen)?
+//           : OpenBracket ((pattern|ELLIPSIS IDENTIFIER?) (COMMA (pattern|ELLIPSIS IDENTIFIER?))*)? CloseBracket
+//           : OpenBrace (pattern COLON pattern (COMMA pattern COLON pattern)*)? CloseBrace
+
+// NUMBER_TOKENS are the tokens of number literals
+var NUMBER_TOKENS = []lexer.TokenType{lexer.IntTT, lexer.FloatTT, lexer.DecimalTT, lexer.ImaginaryTT}
+
+// COMPOUND_ASSIGN_TOKENS are the operators of 'target op= value'
+var COMPOUND_ASSIGN_TOKENS = []lexer.TokenType{lexer.PlusEqualsTT, lexer.MinusEqualsTT, lexer.MultiplyEqualsTT, lexer.DivideEqualsTT, lexer.ModEqualsTT, lexer.PowerEqualsTT}
+
+// LITERAL_NAMES are the predefined constants matched by value in patterns
+var LITERAL_NAMES = []string{"true", "false", "null"}
+
 type Parser struct {
 	tokens          []*lexer.Token
 	currentPosition int
 	currentToken    *lexer.Token
+	yieldFound      bool // a 'yield' was found in the function being parsed
+	noConvert       bool // 'to' ends the expression, as in 'for i = 0 to n'
 }
 
 func NewParser(tokens []*lexer.Token) *Parser {
@@ -73,6 +182,14 @@ func (pars *Parser) advance() *lexer.Token {
 	return pars.currentToken
 }
 
+func (pars *Parser) peek() *lexer.Token {
+	if pars.currentPosition+1 < len(pars.tokens) {
+		return pars.tokens[pars.currentPosition+1]
+	}
+
+	return pars.currentToken
+}
+
 func (pars *Parser) forExpr() (AstNode, error) {
 	if !pars.currentToken.Matches(lexer.KeywordTT, "for") {
 		return nil, utils.InvalidSyntaxError("Expected 'for'")
@@ -80,20 +197,20 @@ func (pars *Parser) forExpr() (AstNode, error) {
 
 	pars.advance()
 
-	if pars.currentToken.Type != lexer.IdentifierTT {
-		return nil, utils.InvalidSyntaxError("Expected identifier")
+	if pars.currentToken.Type != lexer.IdentifierTT || pars.peek().Type != lexer.EqualsTT {
+		return pars.forInExpr()
 	}
 
 	varName := pars.currentToken
 	pars.advance()
 
-	if pars.currentToken.Type != lexer.EqualsTT {
-		return nil, utils.InvalidSyntaxError("Expected '='")
-	}
-
 	pars.advance()
 
+	outerNoConvert := pars.noConvert
+	pars.noConvert = true
 	startValue, err := pars.expr()
+	pars.noConvert = outerNoConvert
+
 	if err != nil {
 		return nil, err
 	}
@@ -135,6 +252,38 @@ func (pars *Parser) forExpr() (AstNode, error) {
 	return NewForNode(varName, startValue, endValue, stepValue, body), nil
 }
 
+// forInExpr parses the rest of a 'for' loop over an iterable, after 'for'
+func (pars *Parser) forInExpr() (AstNode, error) {
+	pattern, err := pars.pattern()
+	if err != nil {
+		return nil, err
+	}
+
+	if !pars.currentToken.Matches(lexer.KeywordTT, "in") {
+		return nil, utils.InvalidSyntaxError("Expected 'in' or '='")
+	}
+
+	pars.advance()
+
+	iterable, err := pars.expr()
+	if err != nil {
+		return nil, err
+	}
+
+	if !pars.currentToken.Matches(lexer.KeywordTT, "then") {
+		return nil, utils.InvalidSyntaxError("Expected 'then'")
+	}
+
+	pars.advance()
+
+	body, err := pars.expr()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewForInNode(pattern, iterable, body), nil
+}
+
 func (pars *Parser) whileExpr() (AstNode, error) {
 
 	if !pars.currentToken.Matches(lexer.KeywordTT, "while") {
@@ -163,6 +312,41 @@ func (pars *Parser) whileExpr() (AstNode, error) {
 	return NewWhileNode(condition, body), nil
 }
 
+// collectExpr parses a loop returning the list of its body results
+func (pars *Parser) collectExpr() (AstNode, error) {
+	if !pars.currentToken.Matches(lexer.KeywordTT, "collect") {
+		return nil, utils.InvalidSyntaxError("Expected 'collect'")
+	}
+
+	pars.advance()
+
+	var loop AstNode
+	var err error
+
+	if pars.currentToken.Matches(lexer.KeywordTT, "for") {
+		loop, err = pars.forExpr()
+	} else if pars.currentToken.Matches(lexer.KeywordTT, "while") {
+		loop, err = pars.whileExpr()
+	} else {
+		return nil, utils.InvalidSyntaxError("Expected 'for' or 'while'")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch loop := loop.(type) {
+	case *ForNode:
+		loop.Collect = true
+	case *ForInNode:
+		loop.Collect = true
+	case *WhileNode:
+		loop.Collect = true
+	}
+
+	return loop, nil
+}
+
 func (pars *Parser) funcDef() (AstNode, error) {
 	if !pars.currentToken.Matches(lexer.KeywordTT, "fun") {
 		return nil, utils.InvalidSyntaxError("Expected 'fun'")
@@ -173,209 +357,1229 @@ func (pars *Parser) funcDef() (AstNode, error) {
 	var varName *lexer.Token = nil
 	if pars.currentToken.Type == lexer.IdentifierTT {
 		varName = pars.currentToken
-
 		pars.advance()
+	}
 
-		if pars.currentToken.Type != lexer.OpenParenTT {
-			return nil, utils.InvalidSyntaxError("Expected '('")
-		}
-	} else {
-		if pars.currentToken.Type != lexer.OpenParenTT {
-			return nil, utils.InvalidSyntaxError("Expected '('")
-		}
+	args, defaults, restArg, err := pars.params()
+	if err != nil {
+		return nil, err
+	}
+
+	if pars.currentToken.Type != lexer.ArrowTT {
+		return nil, utils.InvalidSyntaxError("Expected '->'")
+	}
+
+	pars.advance()
+
+	// a 'yield' makes the function a generator, unless it belongs to a
+	// function defined inside the body
+	outerYieldFound := pars.yieldFound
+	pars.yieldFound = false
+
+	node, err := pars.expr()
+
+	if err != nil {
+		return nil, err
+	}
+
+	isGenerator := pars.yieldFound
+	pars.yieldFound = outerYieldFound
+
+	return NewFuncDefNode(varName, args, defaults, restArg, node, isGenerator), nil
+}
+
+// params parses the parameter list of a function definition, parameters
+// with a default value can't be followed by parameters without one and
+// every parameter, the rest one included, must have a distinct name
+func (pars *Parser) params() ([]*lexer.Token, []AstNode, *lexer.Token, error) {
+	if pars.currentToken.Type != lexer.OpenParenTT {
+		return nil, nil, nil, utils.InvalidSyntaxError("Expected '('")
 	}
 
 	pars.advance()
 
 	args := make([]*lexer.Token, 0)
+	defaults := make([]AstNode, 0)
+	var restArg *lexer.Token
 
-	if pars.currentToken.Type == lexer.IdentifierTT {
-		args = append(args, pars.currentToken)
+	isDuplicate := func(name *lexer.Token) bool {
+		return slices.ContainsFunc(args, func(arg *lexer.Token) bool { return arg.Value == name.Value })
+	}
+
+	if pars.currentToken.Type == lexer.CloseParenTT {
 		pars.advance()
+		return args, defaults, nil, nil
+	}
 
-		for pars.currentToken.Type == lexer.CommaTT {
+	for {
+		if pars.currentToken.Type == lexer.EllipsisTT {
 			pars.advance()
 
 			if pars.currentToken.Type != lexer.IdentifierTT {
-				return nil, utils.InvalidSyntaxError("Expected identifier")
+				return nil, nil, nil, utils.InvalidSyntaxError("Expected identifier")
+			}
+
+			restArg = pars.currentToken
+			if isDuplicate(restArg) {
+				return nil, nil, nil, utils.InvalidSyntaxError(fmt.Sprintf("Duplicate parameter '%s'", restArg.Value))
 			}
 
-			args = append(args, pars.currentToken)
 			pars.advance()
+
+			if pars.currentToken.Type != lexer.CloseParenTT {
+				return nil, nil, nil, utils.InvalidSyntaxError("Expected ')' (after rest parameter)")
+			}
+
+			break
 		}
 
-		if pars.currentToken.Type != lexer.CloseParenTT {
-			return nil, utils.InvalidSyntaxError("Expected ')'")
+		if pars.currentToken.Type != lexer.IdentifierTT {
+			return nil, nil, nil, utils.InvalidSyntaxError("Expected identifier or '...'")
 		}
-	} else {
-		if pars.currentToken.Type != lexer.CloseParenTT {
-			return nil, utils.InvalidSyntaxError("Expected identifier or ')'")
+
+		if isDuplicate(pars.currentToken) {
+			return nil, nil, nil, utils.InvalidSyntaxError(fmt.Sprintf("Duplicate parameter '%s'", pars.currentToken.Value))
 		}
-	}
 
-	pars.advance()
+		args = append(args, pars.currentToken)
+		pars.advance()
 
-	if pars.currentToken.Type != lexer.ArrowTT {
-		return nil, utils.InvalidSyntaxError("Expected '->'")
-	}
+		var defaultValue AstNode
+		if pars.currentToken.Type == lexer.EqualsTT {
+			pars.advance()
 
-	pars.advance()
+			var err error
+			defaultValue, err = pars.expr()
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		} else if len(defaults) > 0 && defaults[len(defaults)-1] != nil {
+			return nil, nil, nil, utils.InvalidSyntaxError("Expected '=' (parameter without default after parameter with default)")
+		}
 
-	node, err := pars.expr()
+		defaults = append(defaults, defaultValue)
 
-	if err != nil {
-		return nil, err
+		if pars.currentToken.Type != lexer.CommaTT {
+			break
+		}
+
+		pars.advance()
+	}
+
+	if pars.currentToken.Type != lexer.CloseParenTT {
+		return nil, nil, nil, utils.InvalidSyntaxError("Expected ',' or ')'")
 	}
 
-	return NewFuncDefNode(varName, args, node), nil
+	pars.advance()
+	return args, defaults, restArg, nil
 }
 
-func (pars *Parser) call() (AstNode, error) {
-	atom, err := pars.atom()
-	if err != nil {
-		return nil, err
+// identifierList parses a parenthesized, comma separated list of identifiers
+func (pars *Parser) identifierList() ([]*lexer.Token, error) {
+	if pars.currentToken.Type != lexer.OpenParenTT {
+		return nil, utils.InvalidSyntaxError("Expected '('")
 	}
 
-	if pars.currentToken.Type == lexer.OpenParenTT {
-		pars.advance()
-		args := make([]AstNode, 0)
+	pars.advance()
 
-		if pars.currentToken.Type == lexer.CloseParenTT {
-			pars.advance()
-		} else {
-			newArg, err := pars.expr()
-			if err != nil {
-				return nil, err
-			}
-			args = append(args, newArg)
+	ids := make([]*lexer.Token, 0)
 
-			for pars.currentToken.Type == lexer.CommaTT {
-				pars.advance()
+	if pars.currentToken.Type == lexer.IdentifierTT {
+		ids = append(ids, pars.currentToken)
+		pars.advance()
 
-				newArg, err = pars.expr()
-				if err != nil {
-					return nil, err
-				}
-				args = append(args, newArg)
-			}
+		for pars.currentToken.Type == lexer.CommaTT {
+			pars.advance()
 
-			if pars.currentToken.Type != lexer.CloseParenTT {
-				return nil, utils.InvalidSyntaxError("Expected ')'")
+			if pars.currentToken.Type != lexer.IdentifierTT {
+				return nil, utils.InvalidSyntaxError("Expected identifier")
 			}
 
+			ids = append(ids, pars.currentToken)
 			pars.advance()
 		}
+	}
 
-		return NewCallNode(atom, args), nil
+	if pars.currentToken.Type != lexer.CloseParenTT {
+		return nil, utils.InvalidSyntaxError("Expected identifier or ')'")
 	}
 
-	return atom, nil
+	pars.advance()
+
+	return ids, nil
 }
 
-func (pars *Parser) ifExpr() (AstNode, error) {
-	var cases = make([][]AstNode, 0)
-	var elseCase AstNode = nil
+// implementsList parses the traits declared by a struct or class, 'implements'
+// is matched as an identifier so it stays usable as the builtin's name
+func (pars *Parser) implementsList() ([]*lexer.Token, error) {
+	traits := make([]*lexer.Token, 0)
 
-	if !pars.currentToken.Matches(lexer.KeywordTT, "if") {
-		return nil, utils.InvalidSyntaxError("Expected 'if'")
+	if !pars.currentToken.Matches(lexer.IdentifierTT, "implements") {
+		return traits, nil
 	}
 
 	pars.advance()
 
-	condition, err := pars.expr()
-	if err != nil {
-		return nil, err
-	}
-
-	if !pars.currentToken.Matches(lexer.KeywordTT, "then") {
-		return nil, utils.InvalidSyntaxError("Expected 'then'")
+	if pars.currentToken.Type != lexer.IdentifierTT {
+		return nil, utils.InvalidSyntaxError("Expected identifier")
 	}
 
+	traits = append(traits, pars.currentToken)
 	pars.advance()
 
-	expr, err := pars.expr()
-	if err != nil {
-		return nil, err
-	}
-	cases = append(cases, []AstNode{condition, expr})
-
-	for pars.currentToken.Matches(lexer.KeywordTT, "elif") {
+	for pars.currentToken.Type == lexer.CommaTT {
 		pars.advance()
 
-		condition, err = pars.expr()
-		if err != nil {
-			return nil, err
-		}
-
-		if !pars.currentToken.Matches(lexer.KeywordTT, "then") {
-			return nil, utils.InvalidSyntaxError("Expected 'then'")
+		if pars.currentToken.Type != lexer.IdentifierTT {
+			return nil, utils.InvalidSyntaxError("Expected identifier")
 		}
 
+		traits = append(traits, pars.currentToken)
 		pars.advance()
+	}
 
-		expr, err = pars.expr()
-		if err != nil {
-			return nil, err
-		}
+	return traits, nil
+}
 
-		cases = append(cases, []AstNode{condition, expr})
+func (pars *Parser) methodBlock() ([]*FuncDefNode, error) {
+	if pars.currentToken.Type != lexer.OpenBraceTT {
+		return nil, utils.InvalidSyntaxError("Expected '{'")
 	}
 
-	if pars.currentToken.Matches(lexer.KeywordTT, "else") {
-		pars.advance()
+	pars.advance()
 
-		elseCase, err = pars.expr()
+	methods := make([]*FuncDefNode, 0)
+
+	for pars.currentToken.Matches(lexer.KeywordTT, "fun") {
+		method, err := pars.funcDef()
 		if err != nil {
 			return nil, err
 		}
+
+		funcDef := method.(*FuncDefNode)
+		if funcDef.VarName == nil {
+			return nil, utils.InvalidSyntaxError("Expected method name")
+		}
+
+		methods = append(methods, funcDef)
 	}
 
-	return NewIfNode(cases, elseCase), nil
+	if pars.currentToken.Type != lexer.CloseBraceTT {
+		return nil, utils.InvalidSyntaxError("Expected 'fun' or '}'")
+	}
+
+	pars.advance()
+
+	return methods, nil
 }
 
-func (pars *Parser) listExpr() (AstNode, error) {
-	elements := make([]AstNode, 0)
+func (pars *Parser) structDef() (AstNode, error) {
+	if !pars.currentToken.Matches(lexer.KeywordTT, "struct") {
+		return nil, utils.InvalidSyntaxError("Expected 'struct'")
+	}
 
-	if pars.currentToken.Type != lexer.OpenBracketTT {
-		return nil, utils.InvalidSyntaxError("Expected ']'")
+	pars.advance()
+
+	if pars.currentToken.Type != lexer.IdentifierTT {
+		return nil, utils.InvalidSyntaxError("Expected identifier")
 	}
 
+	varName := pars.currentToken
 	pars.advance()
 
-	if pars.currentToken.Type == lexer.CloseBracketTT {
-		pars.advance()
-	} else {
-		newEl, err := pars.expr()
+	fields, err := pars.identifierList()
+	if err != nil {
+		return nil, err
+	}
+
+	traits, err := pars.implementsList()
+	if err != nil {
+		return nil, err
+	}
+
+	methods := make([]*FuncDefNode, 0)
+
+	if pars.currentToken.Type == lexer.OpenBraceTT {
+		methods, err = pars.methodBlock()
 		if err != nil {
 			return nil, err
 		}
-		elements = append(elements, newEl)
+	}
 
-		for pars.currentToken.Type == lexer.CommaTT {
-			pars.advance()
+	return NewStructDefNode(varName, fields, traits, methods), nil
+}
+
+func (pars *Parser) classDef() (AstNode, error) {
+	if !pars.currentToken.Matches(lexer.KeywordTT, "class") {
+		return nil, utils.InvalidSyntaxError("Expected 'class'")
+	}
+
+	pars.advance()
+
+	if pars.currentToken.Type != lexer.IdentifierTT {
+		return nil, utils.InvalidSyntaxError("Expected identifier")
+	}
+
+	varName := pars.currentToken
+	pars.advance()
+
+	var parent *lexer.Token = nil
+
+	if pars.currentToken.Matches(lexer.KeywordTT, "extends") {
+		pars.advance()
+
+		if pars.currentToken.Type != lexer.IdentifierTT {
+			return nil, utils.InvalidSyntaxError("Expected identifier")
+		}
+
+		parent = pars.currentToken
+		pars.advance()
+	}
+
+	traits, err := pars.implementsList()
+	if err != nil {
+		return nil, err
+	}
+
+	methods, err := pars.methodBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClassDefNode(varName, parent, traits, methods), nil
+}
+
+func (pars *Parser) traitDef() (AstNode, error) {
+	if !pars.currentToken.Matches(lexer.KeywordTT, "trait") {
+		return nil, utils.InvalidSyntaxError("Expected 'trait'")
+	}
+
+	pars.advance()
+
+	if pars.currentToken.Type != lexer.IdentifierTT {
+		return nil, utils.InvalidSyntaxError("Expected identifier")
+	}
+
+	varName := pars.currentToken
+	pars.advance()
+
+	if pars.currentToken.Type != lexer.OpenBraceTT {
+		return nil, utils.InvalidSyntaxError("Expected '{'")
+	}
+
+	pars.advance()
+
+	methods := make([]*TraitMethod, 0)
+
+	for pars.currentToken.Type == lexer.IdentifierTT {
+		name := pars.currentToken
+		pars.advance()
+
+		args, err := pars.identifierList()
+		if err != nil {
+			return nil, err
+		}
+
+		methods = append(methods, &TraitMethod{Name: name, Args: args})
+
+		if pars.currentToken.Type != lexer.CommaTT {
+			break
+		}
+
+		pars.advance()
+	}
+
+	if pars.currentToken.Type != lexer.CloseBraceTT {
+		return nil, utils.InvalidSyntaxError("Expected identifier or '}'")
+	}
+
+	pars.advance()
+
+	return NewTraitDefNode(varName, methods), nil
+}
+
+func (pars *Parser) enumDef() (AstNode, error) {
+	if !pars.currentToken.Matches(lexer.KeywordTT, "enum") {
+		return nil, utils.InvalidSyntaxError("Expected 'enum'")
+	}
+
+	pars.advance()
+
+	if pars.currentToken.Type != lexer.IdentifierTT {
+		return nil, utils.InvalidSyntaxError("Expected identifier")
+	}
+
+	varName := pars.currentToken
+	pars.advance()
+
+	if pars.currentToken.Type != lexer.OpenBraceTT {
+		return nil, utils.InvalidSyntaxError("Expected '{'")
+	}
+
+	pars.advance()
+
+	variants := make([]*EnumVariant, 0)
+
+	for pars.currentToken.Type == lexer.IdentifierTT {
+		variant := &EnumVariant{Name: pars.currentToken}
+		pars.advance()
+
+		if pars.currentToken.Type == lexer.OpenParenTT {
+			fields, err := pars.identifierList()
+			if err != nil {
+				return nil, err
+			}
+			variant.Fields = fields
+		}
+
+		variants = append(variants, variant)
+
+		if pars.currentToken.Type != lexer.CommaTT {
+			break
+		}
+
+		pars.advance()
+	}
+
+	if pars.currentToken.Type != lexer.CloseBraceTT {
+		return nil, utils.InvalidSyntaxError("Expected identifier or '}'")
+	}
+
+	pars.advance()
+
+	return NewEnumDefNode(varName, variants), nil
+}
+
+func (pars *Parser) tryExpr() (AstNode, error) {
+	if !pars.currentToken.Matches(lexer.KeywordTT, "try") {
+		return nil, utils.InvalidSyntaxError("Expected 'try'")
+	}
+
+	pars.advance()
+
+	body, err := pars.expr()
+	if err != nil {
+		return nil, err
+	}
+
+	var catchVar *lexer.Token
+	var catch, finally AstNode
+
+	if pars.currentToken.Matches(lexer.KeywordTT, "catch") {
+		pars.advance()
+
+		if pars.currentToken.Type != lexer.IdentifierTT {
+			return nil, utils.InvalidSyntaxError("Expected identifier")
+		}
+
+		catchVar = pars.currentToken
+		pars.advance()
+
+		if !pars.currentToken.Matches(lexer.KeywordTT, "then") {
+			return nil, utils.InvalidSyntaxError("Expected 'then'")
+		}
+
+		pars.advance()
+
+		catch, err = pars.expr()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if pars.currentToken.Matches(lexer.KeywordTT, "finally") {
+		pars.advance()
+
+		finally, err = pars.expr()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if catch == nil && finally == nil {
+		return nil, utils.InvalidSyntaxError("Expected 'catch' or 'finally'")
+	}
+
+	return NewTryNode(body, catchVar, catch, finally), nil
+}
+
+func (pars *Parser) matchExpr() (AstNode, error) {
+	if !pars.currentToken.Matches(lexer.KeywordTT, "match") {
+		return nil, utils.InvalidSyntaxError("Expected 'match'")
+	}
+
+	pars.advance()
+
+	subject, err := pars.expr()
+	if err != nil {
+		return nil, err
+	}
+
+	if pars.currentToken.Type != lexer.OpenBraceTT {
+		return nil, utils.InvalidSyntaxError("Expected '{'")
+	}
+
+	pars.advance()
+
+	arms := make([]*MatchArm, 0)
+
+	for pars.currentToken.Type != lexer.CloseBraceTT {
+		pattern, err := pars.pattern()
+		if err != nil {
+			return nil, err
+		}
+
+		var guard AstNode = nil
+
+		if pars.currentToken.Matches(lexer.KeywordTT, "if") {
+			pars.advance()
+
+			guard, err = pars.expr()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if pars.currentToken.Type != lexer.ArrowTT {
+			return nil, utils.InvalidSyntaxError("Expected '->'")
+		}
+
+		pars.advance()
+
+		body, err := pars.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		arms = append(arms, &MatchArm{Pattern: pattern, Guard: guard, Body: body})
+
+		if pars.currentToken.Type != lexer.CommaTT {
+			break
+		}
+
+		pars.advance()
+	}
+
+	if pars.currentToken.Type != lexer.CloseBraceTT {
+		return nil, utils.InvalidSyntaxError("Expected ',' or '}'")
+	}
+
+	pars.advance()
+
+	return NewMatchNode(subject, arms), nil
+}
+
+func (pars *Parser) pattern() (AstNode, error) {
+	token := pars.currentToken
+
+	if slices.Contains(NUMBER_TOKENS, token.Type) {
+		pars.advance()
+		return NewLiteralPatternNode(NewNumberNode(token)), nil
+	} else if token.Type == lexer.StringTT {
+		pars.advance()
+		return NewLiteralPatternNode(NewStringNode(token)), nil
+	} else if token.Type == lexer.MinusTT {
+		pars.advance()
+
+		if !slices.Contains(NUMBER_TOKENS, pars.currentToken.Type) {
+			return nil, utils.InvalidSyntaxError("Expected int or float")
+		}
+
+		num := NewNumberNode(pars.currentToken)
+		pars.advance()
+
+		return NewLiteralPatternNode(NewUnOpNode(num, token)), nil
+	} else if token.Type == lexer.IdentifierTT && slices.Contains(LITERAL_NAMES, token.Value) {
+		pars.advance()
+		return NewLiteralPatternNode(NewVarAccessNode(token)), nil
+	} else if token.Type == lexer.OpenBracketTT {
+		return pars.listPattern()
+	} else if token.Type == lexer.OpenBraceTT {
+		return pars.mapPattern()
+	} else if token.Type != lexer.IdentifierTT {
+		return nil, utils.InvalidSyntaxError("Expected pattern")
+	}
+
+	path, err := pars.dottedName()
+	if err != nil {
+		return nil, err
+	}
+
+	if pars.currentToken.Type == lexer.OpenParenTT {
+		pars.advance()
+		args := make([]AstNode, 0)
+
+		if pars.currentToken.Type != lexer.CloseParenTT {
+			arg, err := pars.pattern()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+
+			for pars.currentToken.Type == lexer.CommaTT {
+				pars.advance()
+
+				arg, err = pars.pattern()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+			}
+		}
+
+		if pars.currentToken.Type != lexer.CloseParenTT {
+			return nil, utils.InvalidSyntaxError("Expected ')'")
+		}
+
+		pars.advance()
+
+		return NewConstructorPatternNode(path, args), nil
+	}
+
+	if len(path) > 1 {
+		return NewConstructorPatternNode(path, nil), nil
+	}
+
+	if pars.currentToken.Type == lexer.ColonTT {
+		pars.advance()
+
+		if pars.currentToken.Type != lexer.IdentifierTT {
+			return nil, utils.InvalidSyntaxError("Expected type name")
+		}
+
+		typeName, err := pars.dottedName()
+		if err != nil {
+			return nil, err
+		}
+
+		return NewTypePatternNode(path[0], typeName), nil
+	}
+
+	return NewBindingPatternNode(path[0]), nil
+}
+
+// dottedName parses IDENTIFIER (DOT IDENTIFIER)*
+func (pars *Parser) dottedName() ([]*lexer.Token, error) {
+	if pars.currentToken.Type != lexer.IdentifierTT {
+		return nil, utils.InvalidSyntaxError("Expected identifier")
+	}
+
+	path := []*lexer.Token{pars.currentToken}
+	pars.advance()
+
+	for pars.currentToken.Type == lexer.DotTT {
+		pars.advance()
+
+		if pars.currentToken.Type != lexer.IdentifierTT {
+			return nil, utils.InvalidSyntaxError("Expected identifier")
+		}
+
+		path = append(path, pars.currentToken)
+		pars.advance()
+	}
+
+	return path, nil
+}
+
+func (pars *Parser) listPattern() (AstNode, error) {
+	before := make([]AstNode, 0)
+	after := make([]AstNode, 0)
+	var rest *lexer.Token = nil
+
+	if pars.currentToken.Type != lexer.OpenBracketTT {
+		return nil, utils.InvalidSyntaxError("Expected '['")
+	}
+
+	pars.advance()
+
+	for pars.currentToken.Type != lexer.CloseBracketTT {
+		if pars.currentToken.Type == lexer.EllipsisTT {
+			if rest != nil {
+				return nil, utils.InvalidSyntaxError("Only one '...' is allowed in a list pattern")
+			}
+
+			pars.advance()
+
+			if pars.currentToken.Type == lexer.IdentifierTT {
+				rest = pars.currentToken
+				pars.advance()
+			} else {
+				rest = lexer.NewToken(lexer.IdentifierTT, "_")
+			}
+		} else {
+			el, err := pars.pattern()
+			if err != nil {
+				return nil, err
+			}
+
+			if rest == nil {
+				before = append(before, el)
+			} else {
+				after = append(after, el)
+			}
+		}
+
+		if pars.currentToken.Type != lexer.CommaTT {
+			break
+		}
+
+		pars.advance()
+	}
+
+	if pars.currentToken.Type != lexer.CloseBracketTT {
+		return nil, utils.InvalidSyntaxError("Expected ',' or ']'")
+	}
+
+	pars.advance()
+
+	return NewListPatternNode(before, rest, after), nil
+}
+
+func (pars *Parser) mapPattern() (AstNode, error) {
+	keys := make([]AstNode, 0)
+	values := make([]AstNode, 0)
+
+	if pars.currentToken.Type != lexer.OpenBraceTT {
+		return nil, utils.InvalidSyntaxError("Expected '{'")
+	}
+
+	pars.advance()
+
+	for pars.currentToken.Type != lexer.CloseBraceTT {
+		if pars.currentToken.Type == lexer.IdentifierTT && !slices.Contains(LITERAL_NAMES, pars.currentToken.Value) {
+			return nil, utils.InvalidSyntaxError("Expected literal key")
+		}
+
+		key, err := pars.pattern()
+		if err != nil {
+			return nil, err
+		}
+
+		literal, ok := key.(*LiteralPatternNode)
+		if !ok {
+			return nil, utils.InvalidSyntaxError("Expected literal key")
+		}
+
+		if pars.currentToken.Type != lexer.ColonTT {
+			return nil, utils.InvalidSyntaxError("Expected ':'")
+		}
+
+		pars.advance()
+
+		value, err := pars.pattern()
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, literal.Value)
+		values = append(values, value)
+
+		if pars.currentToken.Type != lexer.CommaTT {
+			break
+		}
+
+		pars.advance()
+	}
+
+	if pars.currentToken.Type != lexer.CloseBraceTT {
+		return nil, utils.InvalidSyntaxError("Expected ',' or '}'")
+	}
+
+	pars.advance()
+
+	return NewMapPatternNode(keys, values), nil
+}
+
+func (pars *Parser) call() (AstNode, error) {
+	node, err := pars.atom()
+	if err != nil {
+		return nil, err
+	}
+
+	// when any link of the chain is optional ('?.', '?[' or '?.(') the
+	// chain is wrapped in an OptionalChainNode, so a null found by an
+	// optional link short-circuits the whole chain to null
+	optionalChain := false
+
+	for {
+		optional := false
+
+		if pars.currentToken.Type == lexer.QuestionDotTT {
+			pars.advance()
+			optional = true
+
+			if pars.currentToken.Type == lexer.IdentifierTT {
+				member := NewMemberAccessNode(node, pars.currentToken)
+				member.Optional = true
+				node = member
+				optionalChain = true
+
+				pars.advance()
+				continue
+			}
+
+			if pars.currentToken.Type != lexer.OpenParenTT && pars.currentToken.Type != lexer.OpenBracketTT {
+				return nil, utils.InvalidSyntaxError("Expected identifier, '(' or '['")
+			}
+		} else if pars.currentToken.Type == lexer.QuestionTT {
+			pars.advance()
+			optional = true
+
+			if pars.currentToken.Type != lexer.OpenBracketTT {
+				return nil, utils.InvalidSyntaxError("Expected '['")
+			}
+		}
+
+		if pars.currentToken.Type == lexer.OpenParenTT {
+			pars.advance()
+
+			args, err := pars.callArgs()
+			if err != nil {
+				return nil, err
+			}
+
+			call := NewCallNode(node, args)
+			call.Optional = optional
+			node = call
+		} else if pars.currentToken.Type == lexer.OpenBracketTT {
+			node, err = pars.index(node)
+			if err != nil {
+				return nil, err
+			}
+
+			switch index := node.(type) {
+			case *IndexNode:
+				index.Optional = optional
+			case *SliceNode:
+				index.Optional = optional
+			}
+		} else if pars.currentToken.Type == lexer.DotTT {
+			pars.advance()
+
+			if pars.currentToken.Type != lexer.IdentifierTT {
+				return nil, utils.InvalidSyntaxError("Expected identifier")
+			}
+
+			node = NewMemberAccessNode(node, pars.currentToken)
+			pars.advance()
+		} else {
+			if optionalChain {
+				return NewOptionalChainNode(node), nil
+			}
+
+			return node, nil
+		}
+
+		optionalChain = optionalChain || optional
+	}
+}
+
+// callArgs parses the args of a call, after the '('
+func (pars *Parser) callArgs() ([]AstNode, error) {
+	args := make([]AstNode, 0)
+
+	if pars.currentToken.Type == lexer.CloseParenTT {
+		pars.advance()
+		return args, nil
+	}
+
+	newArg, err := pars.callArg()
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, newArg)
+
+	for pars.currentToken.Type == lexer.CommaTT {
+		pars.advance()
+
+		newArg, err = pars.callArg()
+		if err != nil {
+			return nil, err
+		}
+
+		if newArg.GetType() != KeywordArgNT && args[len(args)-1].GetType() == KeywordArgNT {
+			return nil, utils.InvalidSyntaxError("Expected keyword argument (positional argument after keyword argument)")
+		}
+
+		args = append(args, newArg)
+	}
+
+	if pars.currentToken.Type != lexer.CloseParenTT {
+		return nil, utils.InvalidSyntaxError("Expected ')'")
+	}
+
+	pars.advance()
+	return args, nil
+}
+
+// callArg parses a single argument of a call, which can be a keyword
+// argument (IDENTIFIER EQ expr) or a spread list (ELLIPSIS expr)
+func (pars *Parser) callArg() (AstNode, error) {
+	if pars.currentToken.Type == lexer.EllipsisTT {
+		pars.advance()
+
+		node, err := pars.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		return NewSpreadNode(node), nil
+	}
+
+	if pars.currentToken.Type == lexer.IdentifierTT && pars.peek().Type == lexer.EqualsTT {
+		name := pars.currentToken
+		pars.advance()
+		pars.advance()
+
+		value, err := pars.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		return NewKeywordArgNode(name, value), nil
+	}
+
+	return pars.expr()
+}
+
+func (pars *Parser) index(node AstNode) (AstNode, error) {
+	if pars.currentToken.Type != lexer.OpenBracketTT {
+		return nil, utils.InvalidSyntaxError("Expected '['")
+	}
+
+	pars.advance()
+
+	var start, end, step AstNode
+	var err error
+
+	if pars.currentToken.Type != lexer.ColonTT {
+		start, err = pars.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		if pars.currentToken.Type == lexer.CloseBracketTT {
+			pars.advance()
+			return NewIndexNode(node, start), nil
+		}
+	}
+
+	if pars.currentToken.Type != lexer.ColonTT {
+		return nil, utils.InvalidSyntaxError("Expected ':' or ']'")
+	}
+
+	pars.advance()
+
+	if pars.currentToken.Type != lexer.ColonTT && pars.currentToken.Type != lexer.CloseBracketTT {
+		end, err = pars.expr()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if pars.currentToken.Type == lexer.ColonTT {
+		pars.advance()
+
+		if pars.currentToken.Type != lexer.CloseBracketTT {
+			step, err = pars.expr()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if pars.currentToken.Type != lexer.CloseBracketTT {
+		return nil, utils.InvalidSyntaxError("Expected ']'")
+	}
+
+	pars.advance()
+
+	return NewSliceNode(node, start, end, step), nil
+}
+
+func (pars *Parser) ifExpr() (AstNode, error) {
+	var cases = make([][]AstNode, 0)
+	var elseCase AstNode = nil
+
+	if !pars.currentToken.Matches(lexer.KeywordTT, "if") {
+		return nil, utils.InvalidSyntaxError("Expected 'if'")
+	}
+
+	pars.advance()
+
+	condition, err := pars.expr()
+	if err != nil {
+		return nil, err
+	}
+
+	if !pars.currentToken.Matches(lexer.KeywordTT, "then") {
+		return nil, utils.InvalidSyntaxError("Expected 'then'")
+	}
+
+	pars.advance()
+
+	expr, err := pars.expr()
+	if err != nil {
+		return nil, err
+	}
+	cases = append(cases, []AstNode{condition, expr})
+
+	for pars.currentToken.Matches(lexer.KeywordTT, "elif") {
+		pars.advance()
+
+		condition, err = pars.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		if !pars.currentToken.Matches(lexer.KeywordTT, "then") {
+			return nil, utils.InvalidSyntaxError("Expected 'then'")
+		}
+
+		pars.advance()
+
+		expr, err = pars.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		cases = append(cases, []AstNode{condition, expr})
+	}
+
+	if pars.currentToken.Matches(lexer.KeywordTT, "else") {
+		pars.advance()
+
+		elseCase, err = pars.expr()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewIfNode(cases, elseCase), nil
+}
+
+func (pars *Parser) interpExpr() (AstNode, error) {
+	parts := make([]AstNode, 0)
+
+	if pars.currentToken.Type != lexer.InterpStartTT {
+		return nil, utils.InvalidSyntaxError("Expected '\"'")
+	}
+
+	pars.advance()
+
+	for pars.currentToken.Type != lexer.InterpEndTT {
+		if pars.currentToken.Type == lexer.StringTT {
+			parts = append(parts, NewStringNode(pars.currentToken))
+			pars.advance()
+		} else if pars.currentToken.Type == lexer.InterpOpenTT {
+			pars.advance()
+
+			expr, err := pars.expr()
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, expr)
+
+			if pars.currentToken.Type != lexer.InterpCloseTT {
+				return nil, utils.InvalidSyntaxError("Expected '}'")
+			}
+
+			pars.advance()
+		} else {
+			return nil, utils.InvalidSyntaxError("Expected '}'")
+		}
+	}
+
+	pars.advance()
+
+	return NewInterpNode(parts), nil
+}
+
+func (pars *Parser) listExpr() (AstNode, error) {
+	elements := make([]AstNode, 0)
+
+	if pars.currentToken.Type != lexer.OpenBracketTT {
+		return nil, utils.InvalidSyntaxError("Expected ']'")
+	}
+
+	pars.advance()
+
+	if pars.currentToken.Type == lexer.CloseBracketTT {
+		pars.advance()
+	} else {
+		newEl, err := pars.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		if pars.currentToken.Matches(lexer.KeywordTT, "for") {
+			clauses, err := pars.compClauses()
+			if err != nil {
+				return nil, err
+			}
+
+			if pars.currentToken.Type != lexer.CloseBracketTT {
+				return nil, utils.InvalidSyntaxError("Expected ']'")
+			}
+
+			pars.advance()
+			return NewListCompNode(newEl, clauses), nil
+		}
+
+		elements = append(elements, newEl)
+
+		for pars.currentToken.Type == lexer.CommaTT {
+			pars.advance()
 
 			newEl, err = pars.expr()
 			if err != nil {
 				return nil, err
 			}
-			elements = append(elements, newEl)
+			elements = append(elements, newEl)
+		}
+
+		if pars.currentToken.Type != lexer.CloseBracketTT {
+			return nil, utils.InvalidSyntaxError("Expected ']'")
+		}
+
+		pars.advance()
+	}
+
+	return NewListNode(elements), nil
+}
+
+// compClauses parses the 'for' and 'if' clauses of a comprehension, the
+// first one being a 'for'
+func (pars *Parser) compClauses() ([]*CompClause, error) {
+	clauses := make([]*CompClause, 0)
+
+	for {
+		if pars.currentToken.Matches(lexer.KeywordTT, "for") {
+			pars.advance()
+
+			pattern, err := pars.pattern()
+			if err != nil {
+				return nil, err
+			}
+
+			if !pars.currentToken.Matches(lexer.KeywordTT, "in") {
+				return nil, utils.InvalidSyntaxError("Expected 'in'")
+			}
+
+			pars.advance()
+
+			iterable, err := pars.expr()
+			if err != nil {
+				return nil, err
+			}
+
+			clauses = append(clauses, &CompClause{Pattern: pattern, Iterable: iterable})
+		} else if pars.currentToken.Matches(lexer.KeywordTT, "if") && len(clauses) > 0 {
+			pars.advance()
+
+			condition, err := pars.expr()
+			if err != nil {
+				return nil, err
+			}
+
+			clauses = append(clauses, &CompClause{Condition: condition})
+		} else {
+			return clauses, nil
 		}
+	}
+}
 
-		if pars.currentToken.Type != lexer.CloseBracketTT {
-			return nil, utils.InvalidSyntaxError("Expected ']'")
+func (pars *Parser) mapEntry() (AstNode, AstNode, error) {
+	key, err := pars.expr()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if pars.currentToken.Type != lexer.ColonTT {
+		return nil, nil, utils.InvalidSyntaxError("Expected ':'")
+	}
+
+	pars.advance()
+
+	value, err := pars.expr()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return key, value, nil
+}
+
+func (pars *Parser) mapExpr() (AstNode, error) {
+	keys := make([]AstNode, 0)
+	values := make([]AstNode, 0)
+
+	if pars.currentToken.Type != lexer.OpenBraceTT {
+		return nil, utils.InvalidSyntaxError("Expected '{'")
+	}
+
+	pars.advance()
+
+	if pars.currentToken.Type == lexer.CloseBraceTT {
+		pars.advance()
+	} else {
+		key, value, err := pars.mapEntry()
+		if err != nil {
+			return nil, err
+		}
+
+		if pars.currentToken.Matches(lexer.KeywordTT, "for") {
+			clauses, err := pars.compClauses()
+			if err != nil {
+				return nil, err
+			}
+
+			if pars.currentToken.Type != lexer.CloseBraceTT {
+				return nil, utils.InvalidSyntaxError("Expected '}'")
+			}
+
+			pars.advance()
+			return NewMapCompNode(key, value, clauses), nil
+		}
+
+		keys = append(keys, key)
+		values = append(values, value)
+
+		for pars.currentToken.Type == lexer.CommaTT {
+			pars.advance()
+
+			key, value, err = pars.mapEntry()
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+			values = append(values, value)
+		}
+
+		if pars.currentToken.Type != lexer.CloseBraceTT {
+			return nil, utils.InvalidSyntaxError("Expected '}'")
 		}
 
 		pars.advance()
 	}
 
-	return NewListNode(elements), nil
+	return NewMapNode(keys, values), nil
 }
 
 func (pars *Parser) atom() (AstNode, error) {
 	token := pars.currentToken
 
-	if token.Type == lexer.IntTT || token.Type == lexer.FloatTT {
+	if slices.Contains(NUMBER_TOKENS, token.Type) {
 		pars.advance()
+
+		if pars.isUnit(pars.currentToken) {
+			unit, err := pars.unit()
+			if err != nil {
+				return nil, err
+			}
+
+			return NewQuantityNode(NewNumberNode(token), unit), nil
+		}
+
 		return NewNumberNode(token), nil
 	} else if token.Type == lexer.StringTT {
 		pars.advance()
@@ -385,7 +1589,11 @@ func (pars *Parser) atom() (AstNode, error) {
 		return NewVarAccessNode(token), nil
 	} else if token.Type == lexer.OpenParenTT {
 		pars.advance()
+
+		outerNoConvert := pars.noConvert
+		pars.noConvert = false
 		expr, err := pars.expr()
+		pars.noConvert = outerNoConvert
 
 		if err != nil {
 			return nil, err
@@ -397,24 +1605,70 @@ func (pars *Parser) atom() (AstNode, error) {
 		} else {
 			return nil, utils.InvalidSyntaxError("Expected ')'")
 		}
+	} else if token.Type == lexer.InterpStartTT {
+		return pars.interpExpr()
 	} else if token.Type == lexer.OpenBracketTT {
 		return pars.listExpr()
+	} else if token.Type == lexer.OpenBraceTT {
+		return pars.mapExpr()
 	} else if token.Matches(lexer.KeywordTT, "if") {
 		return pars.ifExpr()
 	} else if token.Matches(lexer.KeywordTT, "for") {
 		return pars.forExpr()
 	} else if token.Matches(lexer.KeywordTT, "while") {
 		return pars.whileExpr()
+	} else if token.Matches(lexer.KeywordTT, "collect") {
+		return pars.collectExpr()
 	} else if token.Matches(lexer.KeywordTT, "fun") {
 		return pars.funcDef()
+	} else if token.Matches(lexer.KeywordTT, "struct") {
+		return pars.structDef()
+	} else if token.Matches(lexer.KeywordTT, "class") {
+		return pars.classDef()
+	} else if token.Matches(lexer.KeywordTT, "trait") {
+		return pars.traitDef()
+	} else if token.Matches(lexer.KeywordTT, "enum") {
+		return pars.enumDef()
+	} else if token.Matches(lexer.KeywordTT, "match") {
+		return pars.matchExpr()
+	} else if token.Matches(lexer.KeywordTT, "try") {
+		return pars.tryExpr()
+	} else if token.Matches(lexer.KeywordTT, "throw") {
+		pars.advance()
+
+		node, err := pars.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		return NewThrowNode(node), nil
+	} else if token.Matches(lexer.KeywordTT, "defer") {
+		pars.advance()
+
+		node, err := pars.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		return NewDeferNode(node), nil
+	} else if token.Matches(lexer.KeywordTT, "yield") {
+		pars.advance()
+
+		node, err := pars.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		pars.yieldFound = true
+		return NewYieldNode(node), nil
 	}
 
 	var errMsg string
 
 	if pars.currentPosition > 1 { // we advanced, so we don't expect the 'var' keyword
-		errMsg = "Expected int, float, identifier, '+', '-', '(', '[', 'if', 'for', 'while' or 'fun'"
+		errMsg = "Expected int, float, identifier, '+', '-', '++', '--', '~', '(', '[', '{', 'if', 'for', 'while' or 'fun'"
 	} else {
-		errMsg = "Expected int, float, identifier, 'var', '+', '-', '(', '[', '!', 'if', 'for', 'while' or 'fun'"
+		errMsg = "Expected int, float, identifier, 'var', '+', '-', '++', '--', '~', '(', '[', '{', '!', 'if', 'for', 'while' or 'fun'"
 	}
 
 	return nil, utils.InvalidSyntaxError(errMsg)
@@ -423,7 +1677,7 @@ func (pars *Parser) atom() (AstNode, error) {
 func (pars *Parser) factor() (AstNode, error) {
 	token := pars.currentToken
 
-	if token.Type == lexer.PlusTT || token.Type == lexer.MinusTT {
+	if token.Type == lexer.PlusTT || token.Type == lexer.MinusTT || token.Type == lexer.TildeTT {
 		pars.advance()
 		factor, err := pars.factor()
 
@@ -434,9 +1688,54 @@ func (pars *Parser) factor() (AstNode, error) {
 		return NewUnOpNode(factor, token), nil
 	}
 
+	if token.Type == lexer.DoublePlusTT || token.Type == lexer.DoubleMinusTT {
+		pars.advance()
+		target, err := pars.postfixExpr()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !isAssignable(target) {
+			return nil, utils.InvalidSyntaxError(fmt.Sprintf("Expected identifier, index or member access (after '%s')", token.Value))
+		}
+
+		return NewIncrementNode(target, token, true), nil
+	}
+
 	return pars.powerExpr()
 }
 
+func (pars *Parser) postfixExpr() (AstNode, error) {
+	node, err := pars.call()
+	if err != nil {
+		return nil, err
+	}
+
+	token := pars.currentToken
+	if token.Type != lexer.DoublePlusTT && token.Type != lexer.DoubleMinusTT {
+		return node, nil
+	}
+
+	if !isAssignable(node) {
+		return nil, utils.InvalidSyntaxError(fmt.Sprintf("Expected identifier, index or member access (before '%s')", token.Value))
+	}
+
+	pars.advance()
+	return NewIncrementNode(node, token, false), nil
+}
+
+// isAssignable reports whether node can be the target of a compound
+// assignment or an increment
+func isAssignable(node AstNode) bool {
+	switch node.(type) {
+	case *VarAccessNode, *IndexNode, *MemberAccessNode:
+		return true
+	}
+
+	return false
+}
+
 func (pars *Parser) binOp(lf, rf func() (AstNode, error), cond func(t *lexer.Token) bool) (AstNode, error) {
 	left, err := lf()
 
@@ -465,6 +1764,50 @@ func (pars *Parser) mathExpr() (AstNode, error) {
 	})
 }
 
+func (pars *Parser) pipeExpr() (AstNode, error) {
+	return pars.binOp(pars.convertExpr, pars.convertExpr, func(t *lexer.Token) bool {
+		return t.Type == lexer.PipeTT
+	})
+}
+
+func (pars *Parser) coalesceExpr() (AstNode, error) {
+	return pars.binOp(pars.logicExpr, pars.logicExpr, func(t *lexer.Token) bool {
+		return t.Type == lexer.DoubleQuestionTT
+	})
+}
+
+func (pars *Parser) logicExpr() (AstNode, error) {
+	return pars.binOp(pars.comp, pars.comp, func(t *lexer.Token) bool {
+		return t.Matches(lexer.KeywordTT, "and") || t.Matches(lexer.KeywordTT, "or")
+	})
+}
+
+func (pars *Parser) bitOrExpr() (AstNode, error) {
+	return pars.binOp(pars.bitXorExpr, pars.bitXorExpr, func(t *lexer.Token) bool {
+		return t.Type == lexer.BarTT
+	})
+}
+
+func (pars *Parser) bitXorExpr() (AstNode, error) {
+	return pars.binOp(pars.bitAndExpr, pars.bitAndExpr, func(t *lexer.Token) bool {
+		return t.Matches(lexer.KeywordTT, "xor")
+	})
+}
+
+func (pars *Parser) bitAndExpr() (AstNode, error) {
+	return pars.binOp(pars.shiftExpr, pars.shiftExpr, func(t *lexer.Token) bool {
+		return t.Type == lexer.AmpersandTT
+	})
+}
+
+// shiftExpr parses '<<' and '>>', which compose functions when the left
+// hand side is callable
+func (pars *Parser) shiftExpr() (AstNode, error) {
+	return pars.binOp(pars.mathExpr, pars.mathExpr, func(t *lexer.Token) bool {
+		return t.Type == lexer.DoubleLessThanTT || t.Type == lexer.DoubleGreaterThanTT
+	})
+}
+
 func (pars *Parser) comp() (AstNode, error) {
 	if pars.currentToken.Matches(lexer.KeywordTT, "not") {
 		opToken := pars.currentToken
@@ -479,20 +1822,128 @@ func (pars *Parser) comp() (AstNode, error) {
 		return NewUnOpNode(node, opToken), nil
 	}
 
-	return pars.binOp(pars.mathExpr, pars.mathExpr, func(t *lexer.Token) bool {
-		return slices.Contains([]lexer.TokenType{lexer.DoubleEqualsTT, lexer.NotEqualsTT, lexer.LessThanTT, lexer.LessThanEqualsTT, lexer.GreaterThanTT, lexer.GreaterThanEqualsTT}, t.Type)
+	return pars.binOp(pars.pipeExpr, pars.pipeExpr, func(t *lexer.Token) bool {
+		return slices.Contains([]lexer.TokenType{lexer.DoubleEqualsTT, lexer.NotEqualsTT, lexer.LessThanTT, lexer.LessThanEqualsTT, lexer.GreaterThanTT, lexer.GreaterThanEqualsTT}, t.Type) ||
+			t.Matches(lexer.KeywordTT, "in")
 	})
 }
 
+func (pars *Parser) convertExpr() (AstNode, error) {
+	node, err := pars.rangeExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	for !pars.noConvert && pars.currentToken.Matches(lexer.KeywordTT, "to") && pars.isUnit(pars.peek()) {
+		pars.advance()
+
+		unit, err := pars.unit()
+		if err != nil {
+			return nil, err
+		}
+
+		node = NewConvertNode(node, unit)
+	}
+
+	return node, nil
+}
+
+func (pars *Parser) isUnit(t *lexer.Token) bool {
+	return t.Type == lexer.IdentifierTT && units.IsUnit(t.Value)
+}
+
+// unit parses units like 'km', 'm/s^2' or 'kg*m^2', a '*' or a '/' only
+// belongs to the unit when a unit follows it
+func (pars *Parser) unit() (units.Unit, error) {
+	unit := units.Unit{}
+	sign := 1
+
+	for {
+		if !pars.isUnit(pars.currentToken) {
+			return nil, utils.InvalidSyntaxError("Expected unit")
+		}
+
+		term := units.Term{Name: pars.currentToken.Value, Power: sign}
+		pars.advance()
+
+		if pars.currentToken.Type == lexer.PowerTT {
+			pars.advance()
+
+			negative := pars.currentToken.Type == lexer.MinusTT
+			if negative {
+				pars.advance()
+			}
+
+			if pars.currentToken.Type != lexer.IntTT {
+				return nil, utils.InvalidSyntaxError("Expected int (after '^' in a unit)")
+			}
+
+			power, err := strconv.Atoi(pars.currentToken.Value)
+			if err != nil {
+				return nil, utils.InvalidSyntaxError("Expected int (after '^' in a unit)")
+			}
+			pars.advance()
+
+			if negative {
+				power = -power
+			}
+			term.Power *= power
+		}
+
+		unit = unit.Mul(units.Unit{term})
+
+		if (pars.currentToken.Type != lexer.MultiplyTT && pars.currentToken.Type != lexer.DivideTT) || !pars.isUnit(pars.peek()) {
+			return unit, nil
+		}
+
+		sign = 1
+		if pars.currentToken.Type == lexer.DivideTT {
+			sign = -1
+		}
+		pars.advance()
+	}
+}
+
+func (pars *Parser) rangeExpr() (AstNode, error) {
+	start, err := pars.bitOrExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if pars.currentToken.Type != lexer.DoubleDotTT && pars.currentToken.Type != lexer.DoubleDotEqualsTT {
+		return start, nil
+	}
+
+	inclusive := pars.currentToken.Type == lexer.DoubleDotEqualsTT
+	pars.advance()
+
+	end, err := pars.bitOrExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	var step AstNode
+	if pars.currentToken.Matches(lexer.KeywordTT, "step") {
+		pars.advance()
+
+		step, err = pars.bitOrExpr()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewRangeNode(start, end, step, inclusive), nil
+}
+
 func (pars *Parser) powerExpr() (AstNode, error) {
-	return pars.binOp(pars.call, pars.factor, func(t *lexer.Token) bool {
+	return pars.binOp(pars.postfixExpr, pars.factor, func(t *lexer.Token) bool {
 		return t.Type == lexer.PowerTT
 	})
 }
 
 func (pars *Parser) term() (AstNode, error) {
 	return pars.binOp(pars.factor, pars.factor, func(t *lexer.Token) bool {
-		return slices.Contains([]lexer.TokenType{lexer.MultiplyTT, lexer.DivideTT, lexer.ModTT}, t.Type)
+		return slices.Contains([]lexer.TokenType{lexer.MultiplyTT, lexer.DivideTT, lexer.DoubleSlashTT, lexer.ModTT}, t.Type)
 	})
 }
 
@@ -500,6 +1951,26 @@ func (pars *Parser) expr() (AstNode, error) {
 	if pars.currentToken.Matches(lexer.KeywordTT, "var") {
 		pars.advance()
 
+		if pars.currentToken.Type == lexer.OpenBracketTT || pars.currentToken.Type == lexer.OpenBraceTT {
+			pattern, err := pars.pattern()
+			if err != nil {
+				return nil, err
+			}
+
+			if pars.currentToken.Type != lexer.EqualsTT {
+				return nil, utils.InvalidSyntaxError("Expected '='")
+			}
+
+			pars.advance()
+			expr, err := pars.expr()
+
+			if err != nil {
+				return nil, err
+			}
+
+			return NewDestructureNode(pattern, expr), nil
+		}
+
 		if pars.currentToken.Type != lexer.IdentifierTT {
 			return nil, utils.InvalidSyntaxError("Expected identifier")
 		}
@@ -521,9 +1992,54 @@ func (pars *Parser) expr() (AstNode, error) {
 		return NewVarAssignNode(varName, expr), nil
 	}
 
-	return pars.binOp(pars.comp, pars.comp, func(t *lexer.Token) bool {
-		return t.Matches(lexer.KeywordTT, "and") || t.Matches(lexer.KeywordTT, "or")
-	})
+	node, err := pars.coalesceExpr()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(COMPOUND_ASSIGN_TOKENS, pars.currentToken.Type) {
+		if !isAssignable(node) {
+			return nil, utils.InvalidSyntaxError("Expected identifier, index or member access (before compound assignment)")
+		}
+
+		opToken := pars.currentToken
+		pars.advance()
+
+		value, err := pars.expr()
+		if err != nil {
+			return nil, err
+		}
+
+		return NewCompoundAssignNode(node, opToken, value), nil
+	}
+
+	if pars.currentToken.Type != lexer.EqualsTT {
+		return node, nil
+	}
+
+	switch target := node.(type) {
+	case *IndexNode:
+		pars.advance()
+		value, err := pars.expr()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return NewIndexAssignNode(target.Node, target.Index, value), nil
+	case *MemberAccessNode:
+		pars.advance()
+		value, err := pars.expr()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return NewMemberAssignNode(target.Node, target.Member, value), nil
+	}
+
+	return node, nil
 }
 
 func (pars *Parser) Parse() (AstNode, error) {
@@ -0,0 +1,475 @@
+package runtime
+
+import (
+	"fmt"
+	"go-interpreter/utils"
+	"slices"
+	"strings"
+)
+
+type BuiltinFunc func(env *Environment, args []RuntimeValue) (RuntimeValue, error)
+
+// BuiltinFunctionValue
+
+type BuiltinFunctionValue struct {
+	Type  ValueType
+	Name  string
+	Arity int // -1 for variadic functions
+	Fn    BuiltinFunc
+}
+
+func NewBuiltinFunctionValue(n string, a int, f BuiltinFunc) *BuiltinFunctionValue {
+	return &BuiltinFunctionValue{
+		Type:  FuncVT,
+		Name:  n,
+		Arity: a,
+		Fn:    f,
+	}
+}
+
+func (b *BuiltinFunctionValue) GetType() ValueType {
+	return b.Type
+}
+
+func (b *BuiltinFunctionValue) GetValue() any {
+	return b.Fn
+}
+
+func (b *BuiltinFunctionValue) Print() string {
+	return fmt.Sprintf("<built-in function %s>", b.Name)
+}
+
+func (b *BuiltinFunctionValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '+'")
+}
+
+func (b *BuiltinFunctionValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '-'")
+}
+
+func (b *BuiltinFunctionValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '*'")
+}
+
+func (b *BuiltinFunctionValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '/'")
+}
+
+func (b *BuiltinFunctionValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '%'")
+}
+
+func (b *BuiltinFunctionValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '^'")
+}
+
+func (b *BuiltinFunctionValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '=='")
+}
+
+func (b *BuiltinFunctionValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '!='")
+}
+
+func (b *BuiltinFunctionValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<'")
+}
+
+func (b *BuiltinFunctionValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>'")
+}
+
+func (b *BuiltinFunctionValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<='")
+}
+
+func (b *BuiltinFunctionValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>='")
+}
+
+func (b *BuiltinFunctionValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'and'")
+}
+
+func (b *BuiltinFunctionValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'or'")
+}
+
+func (b *BuiltinFunctionValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	if b.Arity >= 0 {
+		argsDiff := len(args) - b.Arity
+
+		if argsDiff > 0 {
+			return nil, utils.RuntimeError(fmt.Sprintf("%d too many args passed into '%s'", argsDiff, b.Name))
+		} else if argsDiff < 0 {
+			return nil, utils.RuntimeError(fmt.Sprintf("%d too few args passed into '%s'", argsDiff*-1, b.Name))
+		}
+	}
+
+	return b.Fn(parentEnv, args)
+}
+
+// compose builds the function 'f >> g', which passes its args to f and
+// the result of f to g
+func compose(f, g RuntimeValue) (RuntimeValue, error) {
+	if !isCallable(f) || !isCallable(g) {
+		return nil, utils.RuntimeError("Illegal operation '>>'")
+	}
+
+	name := fmt.Sprintf("%s >> %s", callableName(f), callableName(g))
+
+	return NewBuiltinFunctionValue(name, -1, func(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+		res, err := f.Execute(env, args)
+		if err != nil {
+			return nil, err
+		}
+
+		return g.Execute(env, []RuntimeValue{res})
+	}), nil
+}
+
+func isCallable(v RuntimeValue) bool {
+	switch v.GetType() {
+	case FuncVT, ClassVT, StructTypeVT:
+		return true
+	}
+
+	return false
+}
+
+func callableName(v RuntimeValue) string {
+	switch v := v.(type) {
+	case *FunctionValue:
+		return v.Name
+	case *BuiltinFunctionValue:
+		return v.Name
+	}
+
+	return v.Print()
+}
+
+// builtins
+
+var builtins = []*BuiltinFunctionValue{
+	NewBuiltinFunctionValue("len", 1, builtinLen),
+	NewBuiltinFunctionValue("keys", 1, builtinKeys),
+	NewBuiltinFunctionValue("values", 1, builtinValues),
+	NewBuiltinFunctionValue("entries", 1, builtinEntries),
+	NewBuiltinFunctionValue("has", 2, builtinHas),
+	NewBuiltinFunctionValue("delete", 2, builtinDelete),
+	NewBuiltinFunctionValue("implements", 2, builtinImplements),
+	NewBuiltinFunctionValue("error", -1, builtinError),
+	NewBuiltinFunctionValue("list", 1, builtinList),
+	NewBuiltinFunctionValue("take", 2, builtinTake),
+	NewBuiltinFunctionValue("map", 2, builtinMap),
+	NewBuiltinFunctionValue("filter", 2, builtinFilter),
+	NewBuiltinFunctionValue("zip", -1, builtinZip),
+	NewBuiltinFunctionValue("decimal", 1, builtinDecimal),
+	NewBuiltinFunctionValue("round", -1, builtinRound),
+	NewBuiltinFunctionValue("decimal_context", -1, builtinDecimalContext),
+	NewBuiltinFunctionValue("rational", -1, builtinRational),
+	NewBuiltinFunctionValue("complex", 2, builtinComplex),
+}
+
+func argAsMap(name string, arg RuntimeValue) (*MapValue, error) {
+	m, ok := arg.(*MapValue)
+	if !ok {
+		return nil, utils.RuntimeError(fmt.Sprintf("'%s' expects a map, got '%s'", name, arg.GetType()))
+	}
+
+	return m, nil
+}
+
+func builtinLen(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	sized, ok := args[0].(Sized)
+	if !ok {
+		return nil, utils.RuntimeError(fmt.Sprintf("'%s' has no length", args[0].GetType()))
+	}
+
+	length, err := sized.Len()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIntValue(int64(length)), nil
+}
+
+func builtinKeys(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	m, err := argAsMap("keys", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return NewListValue(m.Keys()), nil
+}
+
+func builtinValues(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	m, err := argAsMap("values", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return NewListValue(m.Values()), nil
+}
+
+// builtinEntries returns the [key, value] pairs of a map
+func builtinEntries(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	m, err := argAsMap("entries", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]RuntimeValue, 0, len(m.Order))
+	for _, k := range m.Order {
+		entry := m.Entries[k]
+		entries = append(entries, NewListValue([]RuntimeValue{entry.Key, entry.Value}))
+	}
+
+	return NewListValue(entries), nil
+}
+
+func builtinHas(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	m, err := argAsMap("has", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := m.Has(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNumberValue(utils.BoolToNumber(found)), nil
+}
+
+func builtinDelete(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	m, err := argAsMap("delete", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Delete(args[1])
+}
+
+func builtinImplements(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	trait, ok := args[1].(*TraitValue)
+	if !ok {
+		return nil, utils.RuntimeError(fmt.Sprintf("'implements' expects a trait, got '%s'", args[1].GetType()))
+	}
+
+	implementer, ok := args[0].(TraitImplementer)
+	if !ok {
+		return NewNumberValue(0), nil
+	}
+
+	return NewNumberValue(utils.BoolToNumber(implementer.Implements(trait))), nil
+}
+
+// builtinError creates an error value, error(message, kind?)
+func builtinError(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	if len(args) == 0 {
+		return nil, utils.RuntimeError("1 too few args passed into 'error'")
+	} else if len(args) > 2 {
+		return nil, utils.RuntimeError(fmt.Sprintf("%d too many args passed into 'error'", len(args)-2))
+	}
+
+	kind := "Error"
+	if len(args) == 2 {
+		if args[1].GetType() != StringVT {
+			return nil, utils.RuntimeError(fmt.Sprintf("'error' expects a string kind, got '%s'", args[1].GetType()))
+		}
+		kind = args[1].Print()
+	}
+
+	return NewErrorValue(kind, args[0].Print()), nil
+}
+
+// builtinList collects the elements of an iterable into a new list
+func builtinList(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	iterator, err := iterate(args[0], env)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]RuntimeValue, 0)
+
+	for {
+		value, ok, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			return NewListValue(elements), nil
+		}
+
+		elements = append(elements, value)
+	}
+}
+
+func builtinTake(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	n, ok := toFloat(args[1])
+	if !ok || n < 0 || !utils.FloatIsInt(n) {
+		return nil, utils.RuntimeError(fmt.Sprintf("'take' expects a non negative integer, got '%s'", args[1].Print()))
+	}
+
+	iterator, err := iterate(args[0], env)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIteratorValue("take", &takeIterator{source: iterator, remaining: int(n)}), nil
+}
+
+func builtinMap(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	iterator, err := iterate(args[0], env)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIteratorValue("map", &mapIterator{source: iterator, fn: args[1], env: env}), nil
+}
+
+func builtinFilter(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	iterator, err := iterate(args[0], env)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIteratorValue("filter", &filterIterator{source: iterator, fn: args[1], env: env}), nil
+}
+
+func builtinZip(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	if len(args) == 0 {
+		return nil, utils.RuntimeError("1 too few args passed into 'zip'")
+	}
+
+	sources := make([]Iterator, 0, len(args))
+
+	for _, arg := range args {
+		iterator, err := iterate(arg, env)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, iterator)
+	}
+
+	return NewIteratorValue("zip", &zipIterator{sources: sources}), nil
+}
+
+// builtinDecimal converts a number or a string to an exact decimal
+func builtinDecimal(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return toDecimal(args[0])
+}
+
+// argAsInt returns an arg which must be an integer
+func argAsInt(name string, arg RuntimeValue) (int, error) {
+	n, ok := toFloat(arg)
+	if !ok || !utils.FloatIsInt(n) {
+		return 0, utils.RuntimeError(fmt.Sprintf("'%s' expects an integer, got '%s'", name, arg.Print()))
+	}
+
+	return int(n), nil
+}
+
+// builtinRound rounds a number, round(x, places?, mode?), places defaults to
+// 0 and mode to the rounding of the decimal context
+func builtinRound(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	if len(args) == 0 {
+		return nil, utils.RuntimeError("1 too few args passed into 'round'")
+	} else if len(args) > 3 {
+		return nil, utils.RuntimeError(fmt.Sprintf("%d too many args passed into 'round'", len(args)-3))
+	}
+
+	if !isNumber(args[0]) {
+		return nil, utils.RuntimeError(fmt.Sprintf("'round' expects a number, got '%s'", args[0].GetType()))
+	}
+
+	places := 0
+	if len(args) > 1 {
+		p, err := argAsInt("round", args[1])
+		if err != nil {
+			return nil, err
+		}
+		places = p
+	}
+
+	mode := decimalContext.Rounding
+	if len(args) > 2 {
+		mode = args[2].Print()
+	}
+
+	return roundNumber(args[0], places, mode)
+}
+
+// builtinDecimalContext sets the precision and the rounding mode of decimal
+// arithmetic, decimal_context(precision?, rounding?), returning the settings
+func builtinDecimalContext(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	if len(args) > 2 {
+		return nil, utils.RuntimeError(fmt.Sprintf("%d too many args passed into 'decimal_context'", len(args)-2))
+	}
+
+	ctx := decimalContext
+
+	if len(args) > 0 {
+		p, err := argAsInt("decimal_context", args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if p <= 0 {
+			return nil, utils.RuntimeError("Decimal precision must be positive")
+		}
+		ctx.Precision = p
+	}
+
+	if len(args) > 1 {
+		ctx.Rounding = args[1].Print()
+		if !slices.Contains(ROUNDING_MODES, ctx.Rounding) {
+			return nil, utils.RuntimeError(fmt.Sprintf("Unknown rounding mode '%s', expected one of %s", ctx.Rounding, strings.Join(ROUNDING_MODES, ", ")))
+		}
+	}
+
+	decimalContext = ctx
+
+	settings := NewMapValue()
+	settings.SetIndex(NewStringValue("precision"), NewIntValue(int64(ctx.Precision)))
+	settings.SetIndex(NewStringValue("rounding"), NewStringValue(ctx.Rounding))
+
+	return settings, nil
+}
+
+// builtinRational builds an exact fraction, rational(x) converts a number
+// or a string like "1/3" and rational(n, d) divides two integers
+func builtinRational(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	if len(args) == 0 {
+		return nil, utils.RuntimeError("1 too few args passed into 'rational'")
+	} else if len(args) > 2 {
+		return nil, utils.RuntimeError(fmt.Sprintf("%d too many args passed into 'rational'", len(args)-2))
+	}
+
+	if len(args) == 1 {
+		return toRational(args[0])
+	}
+
+	for _, arg := range args {
+		if _, ok := arg.(*IntValue); !ok {
+			return nil, utils.RuntimeError(fmt.Sprintf("'rational' expects integers, got '%s'", arg.Print()))
+		}
+	}
+
+	num, _ := toRational(args[0])
+	return num.Divide(args[1])
+}
+
+// builtinComplex builds a complex number from its real and imaginary parts
+func builtinComplex(env *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	re, okRe := toFloat(args[0])
+	im, okIm := toFloat(args[1])
+
+	if !okRe || !okIm {
+		return nil, utils.RuntimeError("'complex' expects two real numbers")
+	}
+
+	return NewComplexValue(complex(re, im)), nil
+}
@@ -0,0 +1,348 @@
+package runtime
+
+import (
+	"fmt"
+	"go-interpreter/utils"
+)
+
+// ClassValue
+
+type ClassValue struct {
+	Type    ValueType
+	Name    string
+	Parent  *ClassValue
+	Methods map[string]*FunctionValue
+	Traits  []*TraitValue
+}
+
+func NewClassValue(n string, p *ClassValue, m map[string]*FunctionValue, t []*TraitValue) *ClassValue {
+	return &ClassValue{
+		Type:    ClassVT,
+		Name:    n,
+		Parent:  p,
+		Methods: m,
+		Traits:  t,
+	}
+}
+
+func (c *ClassValue) GetType() ValueType {
+	return c.Type
+}
+
+func (c *ClassValue) GetValue() any {
+	return c.Methods
+}
+
+func (c *ClassValue) Print() string {
+	return fmt.Sprintf("<class %s>", c.Name)
+}
+
+func (c *ClassValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '+'")
+}
+
+func (c *ClassValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '-'")
+}
+
+func (c *ClassValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '*'")
+}
+
+func (c *ClassValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '/'")
+}
+
+func (c *ClassValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '%'")
+}
+
+func (c *ClassValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '^'")
+}
+
+func (c *ClassValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(c == other)), nil
+}
+
+func (c *ClassValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(c != other)), nil
+}
+
+func (c *ClassValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<'")
+}
+
+func (c *ClassValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>'")
+}
+
+func (c *ClassValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<='")
+}
+
+func (c *ClassValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>='")
+}
+
+func (c *ClassValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'and'")
+}
+
+func (c *ClassValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'or'")
+}
+
+// Execute builds a new instance and runs the 'init' constructor, if any
+func (c *ClassValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return c.ExecuteWithKeywords(parentEnv, args, nil)
+}
+
+func (c *ClassValue) ExecuteWithKeywords(parentEnv *Environment, args []RuntimeValue, kwargs []KeywordArg) (RuntimeValue, error) {
+	instance := NewInstanceValue(c)
+
+	init := c.FindMethod("init")
+	if init == nil {
+		if len(args)+len(kwargs) > 0 {
+			return nil, utils.RuntimeError(fmt.Sprintf("%d too many args passed into '%s'", len(args)+len(kwargs), c.Name))
+		}
+		return instance, nil
+	}
+
+	if _, err := init.Bind(instance).ExecuteWithKeywords(parentEnv, args, kwargs); err != nil {
+		return nil, err
+	}
+
+	return instance, nil
+}
+
+// FindMethod looks up a method in the class and then in its ancestors
+func (c *ClassValue) FindMethod(name string) *FunctionValue {
+	for class := c; class != nil; class = class.Parent {
+		if method, found := class.Methods[name]; found {
+			return method
+		}
+	}
+
+	return nil
+}
+
+// IsSubclassOf reports whether the class is other or one of its descendants
+func (c *ClassValue) IsSubclassOf(other *ClassValue) bool {
+	for class := c; class != nil; class = class.Parent {
+		if class == other {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InstanceValue
+
+type InstanceValue struct {
+	Type   ValueType
+	Class  *ClassValue
+	Fields map[string]RuntimeValue
+}
+
+func NewInstanceValue(c *ClassValue) *InstanceValue {
+	return &InstanceValue{
+		Type:   InstanceVT,
+		Class:  c,
+		Fields: make(map[string]RuntimeValue),
+	}
+}
+
+func (i *InstanceValue) GetType() ValueType {
+	return i.Type
+}
+
+func (i *InstanceValue) GetValue() any {
+	return i.Fields
+}
+
+func (i *InstanceValue) Print() string {
+	return fmt.Sprintf("<%s instance>", i.Class.Name)
+}
+
+func (i *InstanceValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '+'")
+}
+
+func (i *InstanceValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '-'")
+}
+
+func (i *InstanceValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '*'")
+}
+
+func (i *InstanceValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '/'")
+}
+
+func (i *InstanceValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '%'")
+}
+
+func (i *InstanceValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '^'")
+}
+
+func (i *InstanceValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(i == other)), nil
+}
+
+func (i *InstanceValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(i != other)), nil
+}
+
+func (i *InstanceValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<'")
+}
+
+func (i *InstanceValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>'")
+}
+
+func (i *InstanceValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<='")
+}
+
+func (i *InstanceValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>='")
+}
+
+func (i *InstanceValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'and'")
+}
+
+func (i *InstanceValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'or'")
+}
+
+func (i *InstanceValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '()'")
+}
+
+// GetField returns the field with the given name or, if there is none,
+// the method with that name bound to the instance
+func (i *InstanceValue) GetField(name string) (RuntimeValue, error) {
+	if value, found := i.Fields[name]; found {
+		return value, nil
+	}
+
+	if method := i.Class.FindMethod(name); method != nil {
+		return method.Bind(i), nil
+	}
+
+	return nil, utils.RuntimeError(fmt.Sprintf("'%s' has no field '%s'", i.Class.Name, name))
+}
+
+func (i *InstanceValue) SetField(name string, value RuntimeValue) (RuntimeValue, error) {
+	i.Fields[name] = value
+	return value, nil
+}
+
+// SuperValue
+
+// SuperValue is bound to 'super' inside methods and resolves methods
+// starting from the parent of the class defining the running method
+type SuperValue struct {
+	Type     ValueType
+	Instance RuntimeValue
+	Class    *ClassValue
+}
+
+func NewSuperValue(i RuntimeValue, c *ClassValue) *SuperValue {
+	return &SuperValue{
+		Type:     SuperVT,
+		Instance: i,
+		Class:    c,
+	}
+}
+
+func (s *SuperValue) GetType() ValueType {
+	return s.Type
+}
+
+func (s *SuperValue) GetValue() any {
+	return s.Class
+}
+
+func (s *SuperValue) Print() string {
+	return fmt.Sprintf("<super %s>", s.Class.Name)
+}
+
+func (s *SuperValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '+'")
+}
+
+func (s *SuperValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '-'")
+}
+
+func (s *SuperValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '*'")
+}
+
+func (s *SuperValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '/'")
+}
+
+func (s *SuperValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '%'")
+}
+
+func (s *SuperValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '^'")
+}
+
+func (s *SuperValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '=='")
+}
+
+func (s *SuperValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '!='")
+}
+
+func (s *SuperValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<'")
+}
+
+func (s *SuperValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>'")
+}
+
+func (s *SuperValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<='")
+}
+
+func (s *SuperValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>='")
+}
+
+func (s *SuperValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'and'")
+}
+
+func (s *SuperValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'or'")
+}
+
+func (s *SuperValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '()'")
+}
+
+func (s *SuperValue) GetField(name string) (RuntimeValue, error) {
+	if method := s.Class.FindMethod(name); method != nil {
+		return method.Bind(s.Instance), nil
+	}
+
+	return nil, utils.RuntimeError(fmt.Sprintf("'%s' has no method '%s'", s.Class.Name, name))
+}
+
+func (s *SuperValue) SetField(name string, value RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Cannot assign to 'super'")
+}
@@ -0,0 +1,258 @@
+package runtime
+
+import (
+	"fmt"
+	"go-interpreter/utils"
+	"math"
+	"math/cmplx"
+	"strconv"
+)
+
+// ComplexValue
+
+// ComplexValue is a complex number, written as '2 + 3i'
+type ComplexValue struct {
+	Type  ValueType
+	Value complex128
+}
+
+func NewComplexValue(c complex128) *ComplexValue {
+	return &ComplexValue{
+		Type:  ComplexVT,
+		Value: c,
+	}
+}
+
+// toComplex converts any number to a complex one
+func toComplex(v RuntimeValue) (complex128, bool) {
+	if c, ok := v.(*ComplexValue); ok {
+		return c.Value, true
+	}
+
+	f, ok := toFloat(v)
+	return complex(f, 0), ok
+}
+
+func (c *ComplexValue) GetType() ValueType {
+	return c.Type
+}
+
+func (c *ComplexValue) GetValue() any {
+	return c.Value
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func (c *ComplexValue) Print() string {
+	re, im := real(c.Value), imag(c.Value)
+
+	if re == 0 {
+		return formatFloat(im) + "i"
+	} else if im < 0 {
+		return fmt.Sprintf("%s-%si", formatFloat(re), formatFloat(-im))
+	}
+
+	return fmt.Sprintf("%s+%si", formatFloat(re), formatFloat(im))
+}
+
+func (c *ComplexValue) operand(other RuntimeValue, op string) (complex128, error) {
+	o, ok := toComplex(other)
+	if !ok {
+		return 0, utils.RuntimeError(fmt.Sprintf("Illegal operation '%s'", op))
+	}
+
+	return o, nil
+}
+
+func (c *ComplexValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	if w, ok := coerce(c, other); ok {
+		return w.Add(other)
+	}
+
+	o, err := c.operand(other, "+")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewComplexValue(c.Value + o), nil
+}
+
+func (c *ComplexValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	if w, ok := coerce(c, other); ok {
+		return w.Subtract(other)
+	}
+
+	o, err := c.operand(other, "-")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewComplexValue(c.Value - o), nil
+}
+
+func (c *ComplexValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	if w, ok := coerce(c, other); ok {
+		return w.Multiply(other)
+	}
+
+	o, err := c.operand(other, "*")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewComplexValue(c.Value * o), nil
+}
+
+func (c *ComplexValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	if w, ok := coerce(c, other); ok {
+		return w.Divide(other)
+	}
+
+	o, err := c.operand(other, "/")
+	if err != nil {
+		return nil, err
+	}
+
+	if o == 0 {
+		return nil, utils.RuntimeError("Division by 0")
+	}
+
+	return NewComplexValue(c.Value / o), nil
+}
+
+func (c *ComplexValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	if w, ok := coerce(c, other); ok {
+		return w.Mod(other)
+	}
+
+	return nil, utils.RuntimeError("Illegal operation '%' on complex numbers")
+}
+
+// Power multiplies the number by itself for small integer exponents, which
+// is exact where cmplx.Pow isn't, e.g. for 1i ^ 2
+func (c *ComplexValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	if w, ok := coerce(c, other); ok {
+		return w.Power(other)
+	}
+
+	o, err := c.operand(other, "^")
+	if err != nil {
+		return nil, err
+	}
+
+	n := real(o)
+	if imag(o) != 0 || n != math.Trunc(n) || math.Abs(n) > 1024 {
+		return NewComplexValue(cmplx.Pow(c.Value, o)), nil
+	}
+
+	res := complex(1, 0)
+	for i := 0; i < int(math.Abs(n)); i++ {
+		res *= c.Value
+	}
+
+	if n < 0 {
+		if res == 0 {
+			return nil, utils.RuntimeError("Division by 0")
+		}
+		res = 1 / res
+	}
+
+	return NewComplexValue(res), nil
+}
+
+func (c *ComplexValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	if w, ok := coerce(c, other); ok {
+		return w.Equals(other)
+	}
+
+	o, err := c.operand(other, "==")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNumberValue(utils.BoolToNumber(c.Value == o)), nil
+}
+
+func (c *ComplexValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	if w, ok := coerce(c, other); ok {
+		return w.NotEquals(other)
+	}
+
+	o, err := c.operand(other, "!=")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNumberValue(utils.BoolToNumber(c.Value != o)), nil
+}
+
+func (c *ComplexValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	if w, ok := coerce(c, other); ok {
+		return w.LessThan(other)
+	}
+
+	return nil, utils.RuntimeError("Illegal operation '<' on complex numbers")
+}
+
+func (c *ComplexValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	if w, ok := coerce(c, other); ok {
+		return w.GreaterThan(other)
+	}
+
+	return nil, utils.RuntimeError("Illegal operation '>' on complex numbers")
+}
+
+func (c *ComplexValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	if w, ok := coerce(c, other); ok {
+		return w.LessThanEquals(other)
+	}
+
+	return nil, utils.RuntimeError("Illegal operation '<=' on complex numbers")
+}
+
+func (c *ComplexValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	if w, ok := coerce(c, other); ok {
+		return w.GreaterThanEquals(other)
+	}
+
+	return nil, utils.RuntimeError("Illegal operation '>=' on complex numbers")
+}
+
+func (c *ComplexValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'and'")
+}
+
+func (c *ComplexValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'or'")
+}
+
+func (c *ComplexValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '()'")
+}
+
+func (c *ComplexValue) GetField(name string) (RuntimeValue, error) {
+	switch name {
+	case "re":
+		return NewNumberValue(real(c.Value)), nil
+	case "im":
+		return NewNumberValue(imag(c.Value)), nil
+	}
+
+	return nil, utils.RuntimeError(fmt.Sprintf("'Complex' has no field '%s'", name))
+}
+
+func (c *ComplexValue) SetField(name string, value RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Complex numbers are immutable")
+}
+
+// HashKey gives a complex number without imaginary part the key of the
+// Number it equals
+func (c *ComplexValue) HashKey() HashKey {
+	if imag(c.Value) == 0 {
+		return HashKey{Type: NumberVT, Value: real(c.Value)}
+	}
+
+	return HashKey{Type: ComplexVT, Value: c.Value}
+}
@@ -0,0 +1,474 @@
+package runtime
+
+import (
+	"fmt"
+	"go-interpreter/utils"
+	"math"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ROUNDING_MODES are the rounding modes accepted by 'round' and
+// 'decimal_context'
+var ROUNDING_MODES = []string{"half_even", "half_up", "half_down", "up", "down", "ceiling", "floor"}
+
+// DecimalContext holds the settings used by decimal arithmetic, results are
+// rounded to Precision significant digits with the Rounding mode
+type DecimalContext struct {
+	Precision int
+	Rounding  string
+}
+
+var decimalContext = DecimalContext{Precision: 28, Rounding: "half_even"}
+
+// DecimalValue
+
+// DecimalValue is an exact decimal number, its value is Coef * 10^-Scale
+type DecimalValue struct {
+	Type  ValueType
+	Coef  *big.Int
+	Scale int
+}
+
+func NewDecimalValue(c *big.Int, s int) *DecimalValue {
+	return &DecimalValue{
+		Type:  DecimalVT,
+		Coef:  c,
+		Scale: s,
+	}
+}
+
+var decimalRegexp = regexp.MustCompile(`^([+-]?)([0-9]*)(?:\.([0-9]*))?(?:[eE]([+-]?[0-9]+))?$`)
+
+// parseDecimal parses a decimal literal like '-1.10' or '2.5e3'
+func parseDecimal(literal string) (*DecimalValue, error) {
+	parts := decimalRegexp.FindStringSubmatch(strings.TrimSpace(literal))
+	if parts == nil || parts[2]+parts[3] == "" {
+		return nil, utils.RuntimeError(fmt.Sprintf("Invalid decimal '%s'", literal))
+	}
+
+	coef, _ := new(big.Int).SetString(parts[2]+parts[3], 10)
+	if parts[1] == "-" {
+		coef.Neg(coef)
+	}
+
+	scale := len(parts[3])
+	if parts[4] != "" {
+		exp, err := strconv.Atoi(parts[4])
+		if err != nil {
+			return nil, utils.RuntimeError(fmt.Sprintf("Invalid decimal '%s'", literal))
+		}
+		scale -= exp
+	}
+
+	return NewDecimalValue(coef, scale), nil
+}
+
+// toDecimal converts any number to a decimal, floats are taken with the
+// shortest representation printing them, i.e. 0.1 becomes exactly 0.1
+func toDecimal(v RuntimeValue) (*DecimalValue, error) {
+	switch n := v.(type) {
+	case *DecimalValue:
+		return n, nil
+	case *IntValue:
+		return NewDecimalValue(n.big(), 0), nil
+	case *NumberValue:
+		if math.IsInf(n.Value, 0) || math.IsNaN(n.Value) {
+			return nil, utils.RuntimeError(fmt.Sprintf("Cannot convert '%v' to a decimal", n.Value))
+		}
+		return parseDecimal(strconv.FormatFloat(n.Value, 'g', -1, 64))
+	case *RationalValue:
+		return NewDecimalValue(n.Value.Num(), 0).quo(NewDecimalValue(n.Value.Denom(), 0)), nil
+	case *StringValue:
+		return parseDecimal(n.Value)
+	}
+
+	return nil, utils.RuntimeError(fmt.Sprintf("Cannot convert '%s' to a decimal", v.GetType()))
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// numDigits returns the number of decimal digits of |n|
+func numDigits(n *big.Int) int {
+	if n.Sign() == 0 {
+		return 1
+	}
+
+	return len(new(big.Int).Abs(n).String())
+}
+
+// roundCoef divides coef by 10^drop, rounding the result with mode
+func roundCoef(coef *big.Int, drop int, mode string) *big.Int {
+	divisor := pow10(drop)
+	q, r := new(big.Int).QuoRem(coef, divisor, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	sign := coef.Sign()
+	half := new(big.Int).Lsh(r.Abs(r), 1).Cmp(divisor)
+
+	var awayFromZero bool
+	switch mode {
+	case "up":
+		awayFromZero = true
+	case "down":
+		awayFromZero = false
+	case "ceiling":
+		awayFromZero = sign > 0
+	case "floor":
+		awayFromZero = sign < 0
+	case "half_up":
+		awayFromZero = half >= 0
+	case "half_down":
+		awayFromZero = half > 0
+	default:
+		awayFromZero = half > 0 || (half == 0 && q.Bit(0) == 1)
+	}
+
+	if awayFromZero {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+
+	return q
+}
+
+// rescale returns the decimal with exactly scale digits after the point
+func (d *DecimalValue) rescale(scale int, mode string) *DecimalValue {
+	if scale >= d.Scale {
+		return NewDecimalValue(new(big.Int).Mul(d.Coef, pow10(scale-d.Scale)), scale)
+	}
+
+	return NewDecimalValue(roundCoef(d.Coef, d.Scale-scale, mode), scale)
+}
+
+// fit rounds the decimal to the precision of the context
+func (d *DecimalValue) fit() *DecimalValue {
+	for n := numDigits(d.Coef); n > decimalContext.Precision; n = numDigits(d.Coef) {
+		d = d.rescale(d.Scale-(n-decimalContext.Precision), decimalContext.Rounding)
+	}
+
+	return d
+}
+
+// align returns the coefficients of both decimals at their largest scale
+func align(a, b *DecimalValue) (*big.Int, *big.Int, int) {
+	scale := max(a.Scale, b.Scale)
+	return a.rescale(scale, "").Coef, b.rescale(scale, "").Coef, scale
+}
+
+func (d *DecimalValue) cmp(other *DecimalValue) int {
+	a, b, _ := align(d, other)
+	return a.Cmp(b)
+}
+
+// Float converts the decimal to the closest float64
+func (d *DecimalValue) Float() float64 {
+	f, _ := strconv.ParseFloat(d.Print(), 64)
+	return f
+}
+
+func (d *DecimalValue) GetType() ValueType {
+	return d.Type
+}
+
+func (d *DecimalValue) GetValue() any {
+	return d.Print()
+}
+
+func (d *DecimalValue) Print() string {
+	if d.Scale <= 0 {
+		return new(big.Int).Mul(d.Coef, pow10(-d.Scale)).String()
+	}
+
+	digits := new(big.Int).Abs(d.Coef).String()
+	if len(digits) <= d.Scale {
+		digits = strings.Repeat("0", d.Scale-len(digits)+1) + digits
+	}
+
+	sign := ""
+	if d.Coef.Sign() < 0 {
+		sign = "-"
+	}
+
+	point := len(digits) - d.Scale
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// operand converts the right hand side of an operation to a decimal
+func (d *DecimalValue) operand(other RuntimeValue, op string) (*DecimalValue, error) {
+	if !isNumber(other) {
+		return nil, utils.RuntimeError(fmt.Sprintf("Illegal operation '%s'", op))
+	}
+
+	return toDecimal(other)
+}
+
+func (d *DecimalValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(d, other); ok {
+		return c.Add(other)
+	}
+
+	o, err := d.operand(other, "+")
+	if err != nil {
+		return nil, err
+	}
+
+	a, b, scale := align(d, o)
+	return NewDecimalValue(new(big.Int).Add(a, b), scale).fit(), nil
+}
+
+func (d *DecimalValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(d, other); ok {
+		return c.Subtract(other)
+	}
+
+	o, err := d.operand(other, "-")
+	if err != nil {
+		return nil, err
+	}
+
+	a, b, scale := align(d, o)
+	return NewDecimalValue(new(big.Int).Sub(a, b), scale).fit(), nil
+}
+
+func (d *DecimalValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(d, other); ok {
+		return c.Multiply(other)
+	}
+
+	o, err := d.operand(other, "*")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDecimalValue(new(big.Int).Mul(d.Coef, o.Coef), d.Scale+o.Scale).fit(), nil
+}
+
+// Divide computes the quotient to the precision of the context, exact
+// quotients drop the trailing zeros beyond the scale of the operands
+func (d *DecimalValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(d, other); ok {
+		return c.Divide(other)
+	}
+
+	o, err := d.operand(other, "/")
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Coef.Sign() == 0 {
+		return nil, utils.RuntimeError("Division by 0")
+	}
+
+	return d.quo(o), nil
+}
+
+// quo divides by a non zero decimal
+func (d *DecimalValue) quo(o *DecimalValue) *DecimalValue {
+	shift := max(decimalContext.Precision+numDigits(o.Coef)-numDigits(d.Coef)+1, 0)
+	num := new(big.Int).Mul(d.Coef, pow10(shift))
+	q, r := new(big.Int).QuoRem(num, o.Coef, new(big.Int))
+	scale := d.Scale - o.Scale + shift
+
+	if r.Sign() != 0 {
+		// an extra non zero digit keeps the rounding of ties correct
+		q.Mul(q, big.NewInt(10))
+		q.Add(q, big.NewInt(int64(num.Sign()*o.Coef.Sign())))
+		return NewDecimalValue(q, scale+1).fit()
+	}
+
+	res := NewDecimalValue(q, scale).fit()
+
+	ten := big.NewInt(10)
+	for res.Scale > max(d.Scale-o.Scale, 0) && new(big.Int).Rem(res.Coef, ten).Sign() == 0 {
+		res = NewDecimalValue(new(big.Int).Quo(res.Coef, ten), res.Scale-1)
+	}
+
+	return res
+}
+
+func (d *DecimalValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(d, other); ok {
+		return c.Mod(other)
+	}
+
+	o, err := d.operand(other, "%")
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Coef.Sign() == 0 {
+		return nil, utils.RuntimeError("Division by 0")
+	}
+
+	// the remainder takes the sign of the divisor, like the '%' of Ints
+	a, b, scale := align(d, o)
+	res := new(big.Int).Rem(a, b)
+	if res.Sign() != 0 && res.Sign() != b.Sign() {
+		res.Add(res, b)
+	}
+
+	return NewDecimalValue(res, scale).fit(), nil
+}
+
+// Power only accepts integer exponents, keeping the result exact
+func (d *DecimalValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(d, other); ok {
+		return c.Power(other)
+	}
+
+	o, err := d.operand(other, "^")
+	if err != nil {
+		return nil, err
+	}
+
+	exp := o.rescale(0, "down")
+	if exp.cmp(o) != 0 || !exp.Coef.IsInt64() {
+		return nil, utils.RuntimeError(fmt.Sprintf("Decimal exponents must be integers, got '%s'", o.Print()))
+	}
+
+	n := exp.Coef.Int64()
+	res := NewDecimalValue(new(big.Int).Exp(d.Coef, big.NewInt(max(n, -n)), nil), d.Scale*int(max(n, -n))).fit()
+
+	if n < 0 {
+		return NewDecimalValue(big.NewInt(1), 0).Divide(res)
+	}
+
+	return res, nil
+}
+
+func (d *DecimalValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(d, other); ok {
+		return c.Equals(other)
+	}
+
+	o, err := d.operand(other, "==")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNumberValue(utils.BoolToNumber(d.cmp(o) == 0)), nil
+}
+
+func (d *DecimalValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(d, other); ok {
+		return c.NotEquals(other)
+	}
+
+	o, err := d.operand(other, "!=")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNumberValue(utils.BoolToNumber(d.cmp(o) != 0)), nil
+}
+
+func (d *DecimalValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(d, other); ok {
+		return c.LessThan(other)
+	}
+
+	o, err := d.operand(other, "<")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNumberValue(utils.BoolToNumber(d.cmp(o) < 0)), nil
+}
+
+func (d *DecimalValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(d, other); ok {
+		return c.GreaterThan(other)
+	}
+
+	o, err := d.operand(other, ">")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNumberValue(utils.BoolToNumber(d.cmp(o) > 0)), nil
+}
+
+func (d *DecimalValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(d, other); ok {
+		return c.LessThanEquals(other)
+	}
+
+	o, err := d.operand(other, "<=")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNumberValue(utils.BoolToNumber(d.cmp(o) <= 0)), nil
+}
+
+func (d *DecimalValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(d, other); ok {
+		return c.GreaterThanEquals(other)
+	}
+
+	o, err := d.operand(other, ">=")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNumberValue(utils.BoolToNumber(d.cmp(o) >= 0)), nil
+}
+
+func (d *DecimalValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(d.Float()).And(other)
+}
+
+func (d *DecimalValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(d.Float()).Or(other)
+}
+
+func (d *DecimalValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '()'")
+}
+
+// HashKey gives a decimal the key of the Number it equals, if any, so
+// that equal keys find the same entry
+func (d *DecimalValue) HashKey() HashKey {
+	f := d.Float()
+	if back, err := toDecimal(NewNumberValue(f)); err == nil && back.cmp(d) == 0 {
+		return HashKey{Type: NumberVT, Value: f}
+	}
+
+	ten := big.NewInt(10)
+	for d.Scale > 0 && new(big.Int).Rem(d.Coef, ten).Sign() == 0 {
+		d = NewDecimalValue(new(big.Int).Quo(d.Coef, ten), d.Scale-1)
+	}
+
+	return HashKey{Type: DecimalVT, Value: d.Print()}
+}
+
+// roundNumber rounds any number to the given places after the point,
+// keeping its type
+func roundNumber(v RuntimeValue, places int, mode string) (RuntimeValue, error) {
+	if !slices.Contains(ROUNDING_MODES, mode) {
+		return nil, utils.RuntimeError(fmt.Sprintf("Unknown rounding mode '%s', expected one of %s", mode, strings.Join(ROUNDING_MODES, ", ")))
+	}
+
+	d, err := toDecimal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	rounded := d.rescale(places, mode)
+
+	switch v.(type) {
+	case *IntValue:
+		return newBigIntValue(rounded.rescale(0, mode).Coef), nil
+	case *NumberValue:
+		return NewNumberValue(rounded.Float()), nil
+	}
+
+	return rounded, nil
+}
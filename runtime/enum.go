@@ -0,0 +1,130 @@
+package runtime
+
+import (
+	"fmt"
+	"go-interpreter/utils"
+)
+
+// EnumValue
+
+// EnumValue groups the variants of an enum, variants with a payload are
+// struct types and variants without one are struct values with no fields
+type EnumValue struct {
+	Type     ValueType
+	Name     string
+	Variants map[string]RuntimeValue
+	Order    []string
+}
+
+func NewEnumValue(n string) *EnumValue {
+	return &EnumValue{
+		Type:     EnumVT,
+		Name:     n,
+		Variants: make(map[string]RuntimeValue),
+		Order:    make([]string, 0),
+	}
+}
+
+func (e *EnumValue) GetType() ValueType {
+	return e.Type
+}
+
+func (e *EnumValue) GetValue() any {
+	return e.Variants
+}
+
+func (e *EnumValue) Print() string {
+	return fmt.Sprintf("<enum %s>", e.Name)
+}
+
+func (e *EnumValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '+'")
+}
+
+func (e *EnumValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '-'")
+}
+
+func (e *EnumValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '*'")
+}
+
+func (e *EnumValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '/'")
+}
+
+func (e *EnumValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '%'")
+}
+
+func (e *EnumValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '^'")
+}
+
+func (e *EnumValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(e == other)), nil
+}
+
+func (e *EnumValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(e != other)), nil
+}
+
+func (e *EnumValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<'")
+}
+
+func (e *EnumValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>'")
+}
+
+func (e *EnumValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<='")
+}
+
+func (e *EnumValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>='")
+}
+
+func (e *EnumValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'and'")
+}
+
+func (e *EnumValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'or'")
+}
+
+func (e *EnumValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '()'")
+}
+
+func (e *EnumValue) GetField(name string) (RuntimeValue, error) {
+	variant, found := e.Variants[name]
+	if !found {
+		return nil, utils.RuntimeError(fmt.Sprintf("'%s' has no variant '%s'", e.Name, name))
+	}
+
+	return variant, nil
+}
+
+func (e *EnumValue) SetField(name string, value RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError(fmt.Sprintf("Cannot assign to variant '%s' of '%s'", name, e.Name))
+}
+
+// AddVariant registers a variant, fields is nil for variants without payload
+func (e *EnumValue) AddVariant(name string, fields []string) error {
+	if _, found := e.Variants[name]; found {
+		return utils.RuntimeError(fmt.Sprintf("Duplicate variant '%s' in '%s'", name, e.Name))
+	}
+
+	variantType := NewStructTypeValue(name, fields, make(map[string]*FunctionValue), make([]*TraitValue, 0))
+	variantType.Enum = e
+
+	if fields == nil {
+		e.Variants[name] = NewStructValue(variantType, make(map[string]RuntimeValue))
+	} else {
+		e.Variants[name] = variantType
+	}
+
+	e.Order = append(e.Order, name)
+	return nil
+}
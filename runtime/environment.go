@@ -2,12 +2,26 @@ package runtime
 
 import (
 	"fmt"
+	"go-interpreter/parser"
 	"go-interpreter/utils"
 )
 
 type Environment struct {
 	variables map[string]RuntimeValue
 	parent    *Environment
+	frame     *CallFrame // set on the environment created by a function call
+}
+
+// CallFrame holds the expressions deferred during a function call and,
+// for generator functions, the generator the values are yielded to
+type CallFrame struct {
+	deferred  []deferredExpr
+	generator *generator
+}
+
+type deferredExpr struct {
+	node parser.AstNode
+	env  *Environment
 }
 
 func NewEnvironment(p *Environment) *Environment {
@@ -24,9 +38,13 @@ func NewEnvironment(p *Environment) *Environment {
 }
 
 func (env *Environment) init() {
-	env.Set("null", NewNumberValue(0))
+	env.Set("null", NewNullValue())
 	env.Set("true", NewNumberValue(1))
 	env.Set("false", NewNumberValue(0))
+
+	for _, b := range builtins {
+		env.Set(b.Name, b)
+	}
 }
 
 func (env *Environment) Get(varName string) (RuntimeValue, error) {
@@ -40,9 +58,35 @@ func (env *Environment) Get(varName string) (RuntimeValue, error) {
 }
 
 func (env *Environment) Set(varName string, value RuntimeValue) {
+	hold(value)
 	env.variables[varName] = value
 }
 
+// Assign updates a variable in the scope defining it
+func (env *Environment) Assign(varName string, value RuntimeValue) error {
+	for e := env; e != nil; e = e.parent {
+		if _, found := e.variables[varName]; found {
+			hold(value)
+			e.variables[varName] = value
+			return nil
+		}
+	}
+
+	return utils.RuntimeError(fmt.Sprintf("'%s' is not defined", varName))
+}
+
 func (env *Environment) Unset(varName string) {
 	delete(env.variables, varName)
 }
+
+// CallFrame returns the frame of the innermost function call, or nil
+// when not inside a function
+func (env *Environment) CallFrame() *CallFrame {
+	for e := env; e != nil; e = e.parent {
+		if e.frame != nil {
+			return e.frame
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"go-interpreter/utils"
+	"slices"
+)
+
+// ErrorValue
+
+type ErrorValue struct {
+	Type    ValueType
+	Kind    string
+	Message string
+	Stack   []string // names of the functions the error went through, innermost first
+}
+
+func NewErrorValue(k string, m string) *ErrorValue {
+	return &ErrorValue{
+		Type:    ErrorVT,
+		Kind:    k,
+		Message: m,
+		Stack:   make([]string, 0),
+	}
+}
+
+func (e *ErrorValue) GetType() ValueType {
+	return e.Type
+}
+
+func (e *ErrorValue) GetValue() any {
+	return e.Message
+}
+
+func (e *ErrorValue) Print() string {
+	return fmt.Sprintf("%s: %s", e.Kind, e.Message)
+}
+
+func (e *ErrorValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '+'")
+}
+
+func (e *ErrorValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '-'")
+}
+
+func (e *ErrorValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '*'")
+}
+
+func (e *ErrorValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '/'")
+}
+
+func (e *ErrorValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '%'")
+}
+
+func (e *ErrorValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '^'")
+}
+
+func (e *ErrorValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(e == other)), nil
+}
+
+func (e *ErrorValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(e != other)), nil
+}
+
+func (e *ErrorValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<'")
+}
+
+func (e *ErrorValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>'")
+}
+
+func (e *ErrorValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<='")
+}
+
+func (e *ErrorValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>='")
+}
+
+func (e *ErrorValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'and'")
+}
+
+func (e *ErrorValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'or'")
+}
+
+func (e *ErrorValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '()'")
+}
+
+func (e *ErrorValue) GetField(name string) (RuntimeValue, error) {
+	switch name {
+	case "kind":
+		return NewStringValue(e.Kind), nil
+	case "message":
+		return NewStringValue(e.Message), nil
+	case "stack":
+		stack := make([]RuntimeValue, 0, len(e.Stack))
+		for _, frame := range e.Stack {
+			stack = append(stack, NewStringValue(frame))
+		}
+		return NewListValue(stack), nil
+	}
+
+	return nil, utils.RuntimeError(fmt.Sprintf("'%s' has no field '%s'", e.Type, name))
+}
+
+func (e *ErrorValue) SetField(name string, value RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Errors are immutable")
+}
+
+// ThrownError
+
+// ThrownError is the Go error carrying an error value up to the nearest 'try'
+type ThrownError struct {
+	Value *ErrorValue
+}
+
+func (t *ThrownError) Error() string {
+	msg := t.Value.Print()
+
+	for _, frame := range t.Value.Stack {
+		msg += fmt.Sprintf("\n  in '%s'", frame)
+	}
+
+	return msg
+}
+
+// errorValueOf turns any error returned while evaluating into an error value
+func errorValueOf(err error) *ErrorValue {
+	var thrown *ThrownError
+	if errors.As(err, &thrown) {
+		return thrown.Value
+	}
+
+	var baseErr *utils.Error
+	if errors.As(err, &baseErr) {
+		return NewErrorValue(baseErr.Name, baseErr.Details)
+	}
+
+	return NewErrorValue("Error", err.Error())
+}
+
+// withFrame records that err went through the function with the given name,
+// on a copy of the error value so a stored error thrown again doesn't keep
+// the frames of its previous throws
+func withFrame(err error, name string) error {
+	value := *errorValueOf(err)
+	value.Stack = append(slices.Clone(value.Stack), name)
+
+	return &ThrownError{Value: &value}
+}
@@ -0,0 +1,288 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"go-interpreter/utils"
+	goruntime "runtime"
+)
+
+// IteratorValue
+
+// IteratorValue wraps a lazy, single pass sequence such as the one
+// produced by a generator function or by 'map' and 'filter'
+type IteratorValue struct {
+	Type     ValueType
+	Name     string
+	Iterator Iterator
+	Held     bool // stored in a variable, so consumers leave it open
+}
+
+func NewIteratorValue(n string, it Iterator) *IteratorValue {
+	return &IteratorValue{
+		Type:     IteratorVT,
+		Name:     n,
+		Iterator: it,
+	}
+}
+
+func (i *IteratorValue) GetType() ValueType {
+	return i.Type
+}
+
+func (i *IteratorValue) GetValue() any {
+	return i.Iterator
+}
+
+func (i *IteratorValue) Print() string {
+	return fmt.Sprintf("<%s>", i.Name)
+}
+
+func (i *IteratorValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '+'")
+}
+
+func (i *IteratorValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '-'")
+}
+
+func (i *IteratorValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '*'")
+}
+
+func (i *IteratorValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '/'")
+}
+
+func (i *IteratorValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '%'")
+}
+
+func (i *IteratorValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '^'")
+}
+
+func (i *IteratorValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(i == other)), nil
+}
+
+func (i *IteratorValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(i != other)), nil
+}
+
+func (i *IteratorValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<'")
+}
+
+func (i *IteratorValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>'")
+}
+
+func (i *IteratorValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<='")
+}
+
+func (i *IteratorValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>='")
+}
+
+func (i *IteratorValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'and'")
+}
+
+func (i *IteratorValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'or'")
+}
+
+func (i *IteratorValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '()'")
+}
+
+// Iter hands the sequence over to the consumer, which closes it when done,
+// unless a variable still holds it and may resume it later
+func (i *IteratorValue) Iter() (Iterator, error) {
+	if i.Held {
+		return heldIterator{i.Iterator}, nil
+	}
+
+	return i.Iterator, nil
+}
+
+// heldIterator hides the Close method of an iterator held by a variable
+type heldIterator struct {
+	Iterator
+}
+
+// hold marks a value stored in a variable
+func hold(value RuntimeValue) {
+	if i, ok := value.(*IteratorValue); ok {
+		i.Held = true
+	}
+}
+
+// generator
+
+// errGeneratorClosed unwinds the body of a generator that was closed
+// before running to completion, it can't be caught by 'try'
+var errGeneratorClosed = errors.New("generator closed")
+
+// errGeneratorAbandoned unwinds a generator nobody refers to anymore, its
+// deferred expressions and finally clauses are skipped as they would run
+// at a time chosen by the garbage collector
+var errGeneratorAbandoned = fmt.Errorf("%w: abandoned", errGeneratorClosed)
+
+type generatorResult struct {
+	value RuntimeValue
+	done  bool
+	err   error
+}
+
+// generator runs the body of a generator function on its own goroutine,
+// which only runs while the consumer waits in Next, so the two never
+// touch the environment at the same time
+type generator struct {
+	fn        *FunctionValue
+	intr      *Interpreter
+	env       *Environment
+	started   bool
+	done      bool
+	abandoned bool
+	resume    chan struct{}
+	out       chan generatorResult
+	stop      chan struct{}
+	finished  chan struct{}
+}
+
+// generatorHandle is what the consumer holds, its goroutine only knows the
+// generator itself so the handle can be collected once the consumer drops
+// it, which then gets the goroutine stopped
+type generatorHandle struct {
+	*generator
+}
+
+func newGenerator(f *FunctionValue, intr *Interpreter, env *Environment) *generatorHandle {
+	g := &generator{
+		fn:       f,
+		intr:     intr,
+		env:      env,
+		resume:   make(chan struct{}),
+		out:      make(chan generatorResult),
+		stop:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	env.frame.generator = g
+
+	h := &generatorHandle{g}
+	goruntime.SetFinalizer(h, func(h *generatorHandle) {
+		h.abandon()
+	})
+
+	return h
+}
+
+func (g *generator) Next() (RuntimeValue, bool, error) {
+	if g.done {
+		return nil, false, nil
+	}
+
+	if !g.started {
+		g.started = true
+		go g.run()
+	} else {
+		g.resume <- struct{}{}
+	}
+
+	res := <-g.out
+	if res.done {
+		g.done = true
+		return nil, false, res.err
+	}
+
+	return res.value, true, nil
+}
+
+// Close stops a generator that hasn't finished yet, running its deferred
+// expressions, and waits for its goroutine to exit
+func (g *generator) Close() {
+	if g.done {
+		return
+	}
+
+	g.done = true
+
+	if g.started {
+		close(g.stop)
+		<-g.finished
+	}
+}
+
+// abandon stops the goroutine of a generator that can't be resumed anymore
+// without running any of its code
+func (g *generator) abandon() {
+	if g.done || !g.started {
+		return
+	}
+
+	g.done = true
+	g.abandoned = true
+	close(g.stop)
+}
+
+func (g *generator) run() {
+	defer close(g.finished)
+
+	_, err := g.fn.run(g.intr, g.env)
+	if errors.Is(err, errGeneratorClosed) {
+		return
+	}
+
+	select {
+	case g.out <- generatorResult{done: true, err: err}:
+	case <-g.stop:
+	}
+}
+
+// yield hands a value to the consumer and blocks until the next one is requested
+func (g *generator) yield(value RuntimeValue) error {
+	select {
+	case g.out <- generatorResult{value: value}:
+	case <-g.stop:
+		return g.stopError()
+	}
+
+	select {
+	case <-g.resume:
+		return nil
+	case <-g.stop:
+		return g.stopError()
+	}
+}
+
+func (g *generator) stopError() error {
+	if g.abandoned {
+		return errGeneratorAbandoned
+	}
+
+	return errGeneratorClosed
+}
+
+// Closer is implemented by iterators holding resources that must be
+// released when the iteration is abandoned before the end
+type Closer interface {
+	Close()
+}
+
+func closeIterator(it Iterator) {
+	if closer, ok := it.(Closer); ok {
+		closer.Close()
+	}
+}
+
+// finishIterator closes the iterator a loop stopped early because of err,
+// unless the loop is unwinding an abandoned generator, whose own sources
+// are then left to be abandoned in turn
+func finishIterator(it Iterator, err error) {
+	if !errors.Is(err, errGeneratorAbandoned) {
+		closeIterator(it)
+	}
+}
@@ -0,0 +1,412 @@
+package runtime
+
+import (
+	"fmt"
+	"go-interpreter/utils"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// IntValue
+
+// IntValue is an integer backed by an int64, operations overflowing it
+// transparently promote the result to a big.Int
+type IntValue struct {
+	Type  ValueType
+	Value int64
+	Big   *big.Int // set only when the value doesn't fit in an int64
+}
+
+func NewIntValue(n int64) *IntValue {
+	return &IntValue{
+		Type:  IntVT,
+		Value: n,
+	}
+}
+
+// newBigIntValue builds an IntValue from a big.Int, going back to an
+// int64 when the value fits in one
+func newBigIntValue(b *big.Int) *IntValue {
+	if b.IsInt64() {
+		return NewIntValue(b.Int64())
+	}
+
+	return &IntValue{
+		Type: IntVT,
+		Big:  b,
+	}
+}
+
+func (iv *IntValue) big() *big.Int {
+	if iv.Big != nil {
+		return iv.Big
+	}
+
+	return big.NewInt(iv.Value)
+}
+
+// Float converts the integer to the closest float64
+func (iv *IntValue) Float() float64 {
+	if iv.Big != nil {
+		f, _ := new(big.Float).SetInt(iv.Big).Float64()
+		return f
+	}
+
+	return float64(iv.Value)
+}
+
+func (iv *IntValue) GetType() ValueType {
+	return iv.Type
+}
+
+func (iv *IntValue) GetValue() any {
+	if iv.Big != nil {
+		return iv.Big
+	}
+
+	return iv.Value
+}
+
+func (iv *IntValue) Print() string {
+	if iv.Big != nil {
+		return iv.Big.String()
+	}
+
+	return strconv.FormatInt(iv.Value, 10)
+}
+
+func (iv *IntValue) toFloat() *NumberValue {
+	return NewNumberValue(iv.Float())
+}
+
+// promote converts the integer for an operation with a non Int operand,
+// to the operand's type when it is a wider number, to a Number otherwise
+func (iv *IntValue) promote(other RuntimeValue) RuntimeValue {
+	if c, ok := coerce(iv, other); ok {
+		return c
+	}
+
+	return iv.toFloat()
+}
+
+func (iv *IntValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	o, ok := other.(*IntValue)
+	if !ok {
+		return iv.promote(other).Add(other)
+	}
+
+	if iv.Big == nil && o.Big == nil {
+		if sum := iv.Value + o.Value; (sum > iv.Value) == (o.Value > 0) {
+			return NewIntValue(sum), nil
+		}
+	}
+
+	return newBigIntValue(new(big.Int).Add(iv.big(), o.big())), nil
+}
+
+func (iv *IntValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	o, ok := other.(*IntValue)
+	if !ok {
+		return iv.promote(other).Subtract(other)
+	}
+
+	if iv.Big == nil && o.Big == nil {
+		if diff := iv.Value - o.Value; (diff < iv.Value) == (o.Value > 0) {
+			return NewIntValue(diff), nil
+		}
+	}
+
+	return newBigIntValue(new(big.Int).Sub(iv.big(), o.big())), nil
+}
+
+func (iv *IntValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	o, ok := other.(*IntValue)
+	if !ok {
+		return iv.promote(other).Multiply(other)
+	}
+
+	if iv.Big == nil && o.Big == nil && iv.Value != math.MinInt64 && o.Value != math.MinInt64 {
+		if prod := iv.Value * o.Value; iv.Value == 0 || prod/iv.Value == o.Value {
+			return NewIntValue(prod), nil
+		}
+	}
+
+	return newBigIntValue(new(big.Int).Mul(iv.big(), o.big())), nil
+}
+
+// Divide always returns a Number, even when the division is exact, '//'
+// being the integer division
+func (iv *IntValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	o, ok := other.(*IntValue)
+	if !ok {
+		return iv.promote(other).Divide(other)
+	}
+
+	if o.Big == nil && o.Value == 0 {
+		return nil, utils.RuntimeError("Division by 0")
+	}
+
+	f, _ := new(big.Rat).SetFrac(iv.big(), o.big()).Float64()
+	return NewNumberValue(f), nil
+}
+
+// Mod takes the sign of the divisor, matching the flooring of '//'
+func (iv *IntValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	o, ok := other.(*IntValue)
+	if !ok {
+		return iv.promote(other).Mod(other)
+	}
+
+	if o.Big == nil && o.Value == 0 {
+		return nil, utils.RuntimeError("Division by 0")
+	}
+
+	if iv.Big == nil && o.Big == nil {
+		res := iv.Value % o.Value
+		if res != 0 && (res < 0) != (o.Value < 0) {
+			res += o.Value
+		}
+		return NewIntValue(res), nil
+	}
+
+	res := new(big.Int).Rem(iv.big(), o.big())
+	if res.Sign() != 0 && res.Sign() != o.big().Sign() {
+		res.Add(res, o.big())
+	}
+
+	return newBigIntValue(res), nil
+}
+
+// Power stays an integer for non negative exponents
+func (iv *IntValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	o, ok := other.(*IntValue)
+	if !ok || o.Big != nil || o.Value < 0 {
+		return iv.promote(other).Power(other)
+	}
+
+	return newBigIntValue(new(big.Int).Exp(iv.big(), o.big(), nil)), nil
+}
+
+// compare returns the sign of iv - other, other being an Int
+func (iv *IntValue) compare(other *IntValue) int {
+	if iv.Big == nil && other.Big == nil {
+		switch {
+		case iv.Value < other.Value:
+			return -1
+		case iv.Value > other.Value:
+			return 1
+		}
+		return 0
+	}
+
+	return iv.big().Cmp(other.big())
+}
+
+func (iv *IntValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	if res, ok := compareIntFloat(iv, other, "=="); ok {
+		return res, nil
+	}
+
+	o, ok := other.(*IntValue)
+	if !ok {
+		return iv.promote(other).Equals(other)
+	}
+
+	return NewNumberValue(utils.BoolToNumber(iv.compare(o) == 0)), nil
+}
+
+func (iv *IntValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	if res, ok := compareIntFloat(iv, other, "!="); ok {
+		return res, nil
+	}
+
+	o, ok := other.(*IntValue)
+	if !ok {
+		return iv.promote(other).NotEquals(other)
+	}
+
+	return NewNumberValue(utils.BoolToNumber(iv.compare(o) != 0)), nil
+}
+
+func (iv *IntValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	if res, ok := compareIntFloat(iv, other, "<"); ok {
+		return res, nil
+	}
+
+	o, ok := other.(*IntValue)
+	if !ok {
+		return iv.promote(other).LessThan(other)
+	}
+
+	return NewNumberValue(utils.BoolToNumber(iv.compare(o) < 0)), nil
+}
+
+func (iv *IntValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	if res, ok := compareIntFloat(iv, other, ">"); ok {
+		return res, nil
+	}
+
+	o, ok := other.(*IntValue)
+	if !ok {
+		return iv.promote(other).GreaterThan(other)
+	}
+
+	return NewNumberValue(utils.BoolToNumber(iv.compare(o) > 0)), nil
+}
+
+func (iv *IntValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	if res, ok := compareIntFloat(iv, other, "<="); ok {
+		return res, nil
+	}
+
+	o, ok := other.(*IntValue)
+	if !ok {
+		return iv.promote(other).LessThanEquals(other)
+	}
+
+	return NewNumberValue(utils.BoolToNumber(iv.compare(o) <= 0)), nil
+}
+
+func (iv *IntValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	if res, ok := compareIntFloat(iv, other, ">="); ok {
+		return res, nil
+	}
+
+	o, ok := other.(*IntValue)
+	if !ok {
+		return iv.promote(other).GreaterThanEquals(other)
+	}
+
+	return NewNumberValue(utils.BoolToNumber(iv.compare(o) >= 0)), nil
+}
+
+func (iv *IntValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return iv.toFloat().And(other)
+}
+
+func (iv *IntValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return iv.toFloat().Or(other)
+}
+
+func (iv *IntValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '()'")
+}
+
+// HashKey makes an Int and a Number with the same value the same key
+func (iv *IntValue) HashKey() HashKey {
+	if iv.Big == nil && iv.Value >= -1<<53 && iv.Value <= 1<<53 {
+		return HashKey{Type: NumberVT, Value: float64(iv.Value)}
+	}
+
+	return HashKey{Type: IntVT, Value: iv.Print()}
+}
+
+// integerOperand returns the right hand side of an integer operator,
+// Numbers without a fractional part are accepted as well
+func integerOperand(other RuntimeValue, op string) (*IntValue, error) {
+	switch o := other.(type) {
+	case *IntValue:
+		return o, nil
+	case *NumberValue:
+		return o.integer(op)
+	}
+
+	return nil, utils.RuntimeError(fmt.Sprintf("Illegal operation '%s'", op))
+}
+
+func (iv *IntValue) BitAnd(other RuntimeValue) (RuntimeValue, error) {
+	o, err := integerOperand(other, "&")
+	if err != nil {
+		return nil, err
+	}
+
+	return newBigIntValue(new(big.Int).And(iv.big(), o.big())), nil
+}
+
+func (iv *IntValue) BitOr(other RuntimeValue) (RuntimeValue, error) {
+	o, err := integerOperand(other, "|")
+	if err != nil {
+		return nil, err
+	}
+
+	return newBigIntValue(new(big.Int).Or(iv.big(), o.big())), nil
+}
+
+func (iv *IntValue) BitXor(other RuntimeValue) (RuntimeValue, error) {
+	o, err := integerOperand(other, "xor")
+	if err != nil {
+		return nil, err
+	}
+
+	return newBigIntValue(new(big.Int).Xor(iv.big(), o.big())), nil
+}
+
+func (iv *IntValue) BitNot() (RuntimeValue, error) {
+	return newBigIntValue(new(big.Int).Not(iv.big())), nil
+}
+
+// shiftCount validates the right hand side of '<<' and '>>'
+func shiftCount(other RuntimeValue, op string) (uint, error) {
+	o, err := integerOperand(other, op)
+	if err != nil {
+		return 0, err
+	}
+
+	if o.Big != nil || o.Value < 0 {
+		return 0, utils.RuntimeError("Negative shift count")
+	}
+
+	return uint(o.Value), nil
+}
+
+func (iv *IntValue) ShiftLeft(other RuntimeValue) (RuntimeValue, error) {
+	n, err := shiftCount(other, "<<")
+	if err != nil {
+		return nil, err
+	}
+
+	return newBigIntValue(new(big.Int).Lsh(iv.big(), n)), nil
+}
+
+func (iv *IntValue) ShiftRight(other RuntimeValue) (RuntimeValue, error) {
+	n, err := shiftCount(other, ">>")
+	if err != nil {
+		return nil, err
+	}
+
+	return newBigIntValue(new(big.Int).Rsh(iv.big(), n)), nil
+}
+
+// FloorDivide rounds the quotient towards negative infinity
+func (iv *IntValue) FloorDivide(other RuntimeValue) (RuntimeValue, error) {
+	o, err := integerOperand(other, "//")
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Big == nil && o.Value == 0 {
+		return nil, utils.RuntimeError("Division by 0")
+	}
+
+	q, r := new(big.Int).QuoRem(iv.big(), o.big(), new(big.Int))
+	if r.Sign() != 0 && r.Sign() != o.big().Sign() {
+		q.Sub(q, big.NewInt(1))
+	}
+
+	return newBigIntValue(q), nil
+}
+
+// parseInt parses an integer literal of any size
+func parseInt(literal string) (*IntValue, error) {
+	if n, err := strconv.ParseInt(literal, 10, 64); err == nil {
+		return NewIntValue(n), nil
+	}
+
+	b, ok := new(big.Int).SetString(literal, 10)
+	if !ok {
+		return nil, utils.RuntimeError("invalid number")
+	}
+
+	return newBigIntValue(b), nil
+}
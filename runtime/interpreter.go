@@ -1,10 +1,15 @@
 package runtime
 
 import (
+	"errors"
+	"fmt"
 	"go-interpreter/lexer"
 	"go-interpreter/parser"
 	"go-interpreter/utils"
+	"math"
+	"slices"
 	"strconv"
+	"strings"
 )
 
 type Interpreter struct{}
@@ -14,10 +19,20 @@ func NewInterpreter() *Interpreter {
 }
 
 func (intr *Interpreter) visitNumberNode(node *parser.NumberNode) (RuntimeValue, error) {
+	if node.Token.Type == lexer.IntTT {
+		return parseInt(node.Token.Value)
+	} else if node.Token.Type == lexer.DecimalTT {
+		return parseDecimal(node.Token.Value)
+	}
+
 	v, err := strconv.ParseFloat(node.Token.Value, 64)
 	if err != nil {
 		return nil, utils.RuntimeError("invalid number")
 	}
+
+	if node.Token.Type == lexer.ImaginaryTT {
+		return NewComplexValue(complex(0, v)), nil
+	}
 	return NewNumberValue(v), nil
 }
 
@@ -28,10 +43,28 @@ func (intr *Interpreter) visitUnOpNode(node *parser.UnOpNode, env *Environment)
 		return nil, err
 	}
 
+	if node.Operator.Matches(lexer.KeywordTT, "not") && isNull(num) {
+		return NewNumberValue(1), nil
+	}
+
+	if node.Operator.Type == lexer.TildeTT {
+		integral, ok := num.(Integral)
+		if !ok {
+			return nil, utils.RuntimeError(fmt.Sprintf("Illegal operation '~' on '%s'", num.GetType()))
+		}
+		return integral.BitNot()
+	}
+
+	// complex numbers can be negated but have no truth value
+	n, isReal := toFloat(num)
+	if !isNumber(num) || (!isReal && node.Operator.Matches(lexer.KeywordTT, "not")) {
+		return nil, utils.RuntimeError(fmt.Sprintf("Illegal operation '%s' on '%s'", node.Operator.Value, num.GetType()))
+	}
+
 	if node.Operator.Type == lexer.MinusTT {
-		return NewNumberValue(num.(*NumberValue).Value * -1), nil
+		return NewIntValue(-1).Multiply(num)
 	} else if node.Operator.Matches(lexer.KeywordTT, "not") {
-		if num.(*NumberValue).Value == 0 {
+		if n == 0 {
 			return NewNumberValue(1), nil
 		} else {
 			return NewNumberValue(0), nil
@@ -48,12 +81,28 @@ func (intr *Interpreter) visitBinOpNode(node *parser.BinOpNode, env *Environment
 		return nil, err
 	}
 
+	if node.Operation.Type == lexer.PipeTT {
+		return intr.pipe(lhs, node.Right, env)
+	} else if node.Operation.Type == lexer.DoubleQuestionTT {
+		if !isNull(lhs) {
+			return lhs, nil
+		}
+		return intr.Visit(node.Right, env)
+	}
+
 	rhs, err := intr.Visit(node.Right, env)
 
 	if err != nil {
 		return nil, err
 	}
 
+	// comparing with null is always allowed, whatever the other operand
+	if (isNull(lhs) || isNull(rhs)) && node.Operation.Type == lexer.DoubleEqualsTT {
+		return NewNumberValue(utils.BoolToNumber(isNull(lhs) && isNull(rhs))), nil
+	} else if (isNull(lhs) || isNull(rhs)) && node.Operation.Type == lexer.NotEqualsTT {
+		return NewNumberValue(utils.BoolToNumber(isNull(lhs) != isNull(rhs))), nil
+	}
+
 	if node.Operation.Type == lexer.PlusTT {
 		return lhs.Add(rhs)
 	} else if node.Operation.Type == lexer.MinusTT {
@@ -82,10 +131,115 @@ func (intr *Interpreter) visitBinOpNode(node *parser.BinOpNode, env *Environment
 		return lhs.And(rhs)
 	} else if node.Operation.Matches(lexer.KeywordTT, "or") {
 		return lhs.Or(rhs)
+	} else if node.Operation.Type == lexer.DoubleGreaterThanTT && isCallable(lhs) {
+		return compose(lhs, rhs)
+	} else if node.Operation.Type == lexer.DoubleLessThanTT && isCallable(lhs) {
+		return compose(rhs, lhs)
+	} else if slices.Contains(INTEGER_OPERATORS, node.Operation.Type) || node.Operation.Matches(lexer.KeywordTT, "xor") {
+		return integerOperation(node.Operation, lhs, rhs)
+	} else if node.Operation.Matches(lexer.KeywordTT, "in") {
+		container, ok := rhs.(Container)
+		if !ok {
+			return nil, utils.RuntimeError(fmt.Sprintf("Illegal operation 'in' on '%s'", rhs.GetType()))
+		}
+
+		found, err := container.Contains(lhs)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewNumberValue(utils.BoolToNumber(found)), nil
 	}
 	return nil, utils.RuntimeError("Unsupported operation")
 }
 
+// INTEGER_OPERATORS are the binary operators of the Integral interface,
+// apart from the 'xor' keyword
+var INTEGER_OPERATORS = []lexer.TokenType{lexer.AmpersandTT, lexer.BarTT, lexer.DoubleLessThanTT, lexer.DoubleGreaterThanTT, lexer.DoubleSlashTT}
+
+func integerOperation(op *lexer.Token, lhs, rhs RuntimeValue) (RuntimeValue, error) {
+	integral, ok := lhs.(Integral)
+	if !ok {
+		return nil, utils.RuntimeError(fmt.Sprintf("Illegal operation '%s' on '%s'", op.Value, lhs.GetType()))
+	}
+
+	switch op.Type {
+	case lexer.AmpersandTT:
+		return integral.BitAnd(rhs)
+	case lexer.BarTT:
+		return integral.BitOr(rhs)
+	case lexer.DoubleLessThanTT:
+		return integral.ShiftLeft(rhs)
+	case lexer.DoubleGreaterThanTT:
+		return integral.ShiftRight(rhs)
+	case lexer.DoubleSlashTT:
+		return integral.FloorDivide(rhs)
+	}
+
+	return integral.BitXor(rhs)
+}
+
+// pipe calls the right hand side of 'x |> f' with x, when it is a call
+// x is passed before the other args
+func (intr *Interpreter) pipe(value RuntimeValue, node parser.AstNode, env *Environment) (RuntimeValue, error) {
+	if call, ok := node.(*parser.CallNode); ok {
+		return intr.call(call, []RuntimeValue{value}, env)
+	}
+
+	fn, err := intr.Visit(node, env)
+	if err != nil {
+		return nil, err
+	}
+
+	return fn.Execute(env, []RuntimeValue{value})
+}
+
+// errShortCircuit is returned by an optional link finding a null, it
+// stops the evaluation of the enclosing optional chain
+var errShortCircuit = errors.New("optional chain short-circuited")
+
+func (intr *Interpreter) visitOptionalChainNode(node *parser.OptionalChainNode, env *Environment) (RuntimeValue, error) {
+	value, err := intr.Visit(node.Node, env)
+	if errors.Is(err, errShortCircuit) {
+		return NewNullValue(), nil
+	}
+
+	return value, err
+}
+
+func (intr *Interpreter) visitRangeNode(node *parser.RangeNode, env *Environment) (RuntimeValue, error) {
+	bounds := []parser.AstNode{node.Start, node.End}
+	if node.Step != nil {
+		bounds = append(bounds, node.Step)
+	}
+
+	values := []RuntimeValue{nil, nil, NewIntValue(1)}
+
+	for i, bound := range bounds {
+		value, err := intr.Visit(bound, env)
+		if err != nil {
+			return nil, err
+		}
+
+		n, ok := toFloat(value)
+		if !ok {
+			return nil, utils.RuntimeError(fmt.Sprintf("Range bounds must be numbers, got '%s'", value.GetType()))
+		}
+
+		if math.IsInf(n, 0) || math.IsNaN(n) {
+			return nil, utils.RuntimeError(fmt.Sprintf("Range bounds must be finite, got '%s'", value.Print()))
+		}
+
+		if i == 2 && n == 0 {
+			return nil, utils.RuntimeError("Range step cannot be 0")
+		}
+
+		values[i] = value
+	}
+
+	return NewRangeValue(values[0], values[1], values[2], node.Inclusive), nil
+}
+
 func (intr *Interpreter) visitVarAccessNode(node *parser.VarAccessNode, env *Environment) (RuntimeValue, error) {
 	varName := node.VarName.Value
 	value, err := env.Get(varName)
@@ -109,6 +263,20 @@ func (intr *Interpreter) visitVarAssignNode(node *parser.VarAssignNode, env *Env
 	return value, nil
 }
 
+func (intr *Interpreter) visitDestructureNode(node *parser.DestructureNode, env *Environment) (RuntimeValue, error) {
+	value, err := intr.Visit(node.Value, env)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := intr.destructure(node.Pattern, value, env); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
 func (intr *Interpreter) visitIfNode(node *parser.IfNode, env *Environment) (RuntimeValue, error) {
 	for _, c := range node.Cases {
 
@@ -117,7 +285,7 @@ func (intr *Interpreter) visitIfNode(node *parser.IfNode, env *Environment) (Run
 			return nil, err
 		}
 
-		if conditionValue.GetValue() == 1.0 {
+		if isTrue(conditionValue) {
 			return intr.Visit(c[1], env)
 		}
 	}
@@ -126,7 +294,7 @@ func (intr *Interpreter) visitIfNode(node *parser.IfNode, env *Environment) (Run
 		return intr.Visit(node.ElseCase, env)
 	}
 
-	return nil, nil
+	return NewNullValue(), nil
 }
 
 func (intr *Interpreter) visitForNode(node *parser.ForNode, env *Environment) (RuntimeValue, error) {
@@ -139,47 +307,109 @@ func (intr *Interpreter) visitForNode(node *parser.ForNode, env *Environment) (R
 		return nil, err
 	}
 
-	stepValue := NewNumberValue(1)
+	var stepValue RuntimeValue = NewIntValue(1)
 
 	if node.StepValue != nil {
-		sv, err := intr.Visit(node.StepValue, env)
+		stepValue, err = intr.Visit(node.StepValue, env)
 		if err != nil {
 			return nil, err
 		}
-		stepValue = sv.(*NumberValue)
 	}
 
-	i := startValue.(*NumberValue).Value
+	for _, bound := range []RuntimeValue{startValue, endValue, stepValue} {
+		if !isNumber(bound) {
+			return nil, utils.RuntimeError(fmt.Sprintf("For loop bounds must be numbers, got '%s'", bound.GetType()))
+		}
+	}
+
+	i := startValue
 
-	var condition = func() bool {
-		return i < endValue.(*NumberValue).Value
+	var condition = func() (RuntimeValue, error) {
+		return i.LessThan(endValue)
 	}
 
-	if stepValue.Value < 0 {
-		condition = func() bool {
-			return i > endValue.(*NumberValue).Value
+	if step, _ := toFloat(stepValue); step < 0 {
+		condition = func() (RuntimeValue, error) {
+			return i.GreaterThan(endValue)
 		}
 	}
 
-	els := make([]RuntimeValue, 0)
+	var els []RuntimeValue
+
+	for {
+		holds, err := condition()
+		if err != nil {
+			return nil, err
+		}
+
+		if !isTrue(holds) {
+			break
+		}
 
-	for condition() {
-		env.Set(node.VarName.Value, NewNumberValue(i))
-		i += stepValue.Value
+		env.Set(node.VarName.Value, i)
+
+		i, err = i.Add(stepValue)
+		if err != nil {
+			return nil, err
+		}
 
 		el, err := intr.Visit(node.Body, env)
 		if err != nil {
 			return nil, err
 		}
-		els = append(els, el)
+
+		if node.Collect {
+			els = append(els, el)
+		}
 	}
 
-	return NewListValue(els), nil
+	return loopResult(node.Collect, els), nil
 }
 
-func (intr *Interpreter) visitWhileNode(node *parser.WhileNode, env *Environment) (RuntimeValue, error) {
+func (intr *Interpreter) visitForInNode(node *parser.ForInNode, env *Environment) (RuntimeValue, error) {
+	iterable, err := intr.Visit(node.Iterable, env)
+	if err != nil {
+		return nil, err
+	}
+
+	iterator, err := iterate(iterable, env)
+	if err != nil {
+		return nil, err
+	}
 
-	els := make([]RuntimeValue, 0)
+	var els []RuntimeValue
+
+	for {
+		value, ok, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok {
+			break
+		}
+
+		if err := intr.destructure(node.Pattern, value, env); err != nil {
+			finishIterator(iterator, err)
+			return nil, err
+		}
+
+		el, err := intr.Visit(node.Body, env)
+		if err != nil {
+			finishIterator(iterator, err)
+			return nil, err
+		}
+
+		if node.Collect {
+			els = append(els, el)
+		}
+	}
+
+	return loopResult(node.Collect, els), nil
+}
+
+func (intr *Interpreter) visitWhileNode(node *parser.WhileNode, env *Environment) (RuntimeValue, error) {
+	var els []RuntimeValue
 
 	for {
 		condition, err := intr.Visit(node.Condition, env)
@@ -187,7 +417,7 @@ func (intr *Interpreter) visitWhileNode(node *parser.WhileNode, env *Environment
 			return nil, err
 		}
 
-		if condition.GetValue() != 1.0 {
+		if !isTrue(condition) {
 			break
 		}
 
@@ -196,13 +426,29 @@ func (intr *Interpreter) visitWhileNode(node *parser.WhileNode, env *Environment
 			return nil, err
 		}
 
-		els = append(els, el)
+		if node.Collect {
+			els = append(els, el)
+		}
+	}
+
+	return loopResult(node.Collect, els), nil
+}
+
+// loopResult is the value of a loop, only 'collect' loops keep the
+// results of their body
+func loopResult(collect bool, els []RuntimeValue) RuntimeValue {
+	if !collect {
+		return NewNullValue()
 	}
 
-	return NewListValue(els), nil
+	if els == nil {
+		els = make([]RuntimeValue, 0)
+	}
+
+	return NewListValue(els)
 }
 
-func (intr *Interpreter) visitFuncDefNode(node *parser.FuncDefNode, env *Environment) (RuntimeValue, error) {
+func (intr *Interpreter) makeFunction(node *parser.FuncDefNode) *FunctionValue {
 	funcName := "<anonymous>"
 
 	if node.VarName != nil {
@@ -215,16 +461,33 @@ func (intr *Interpreter) visitFuncDefNode(node *parser.FuncDefNode, env *Environ
 	}
 
 	funcValue := NewFunctionValue(funcName, node.Body, argNames)
+	funcValue.Defaults = node.Defaults
+	funcValue.IsGenerator = node.IsGenerator
+
+	if node.RestArg != nil {
+		funcValue.RestArg = node.RestArg.Value
+	}
+
+	return funcValue
+}
+
+func (intr *Interpreter) visitFuncDefNode(node *parser.FuncDefNode, env *Environment) (RuntimeValue, error) {
+	funcValue := intr.makeFunction(node)
 
 	if node.VarName != nil {
-		env.Set(funcName, funcValue)
+		env.Set(funcValue.Name, funcValue)
 	}
 
 	return funcValue, nil
 }
 
 func (intr *Interpreter) visitCallNode(node *parser.CallNode, env *Environment) (RuntimeValue, error) {
-	args := make([]RuntimeValue, 0)
+	return intr.call(node, nil, env)
+}
+
+// call evaluates a call, leading args are passed before the ones of the node
+func (intr *Interpreter) call(node *parser.CallNode, leading []RuntimeValue, env *Environment) (RuntimeValue, error) {
+	args := append(make([]RuntimeValue, 0), leading...)
 
 	funcToCall, err := intr.Visit(node.Node, env)
 
@@ -232,15 +495,50 @@ func (intr *Interpreter) visitCallNode(node *parser.CallNode, env *Environment)
 		return nil, err
 	}
 
+	if node.Optional && isNull(funcToCall) {
+		return nil, errShortCircuit
+	}
+
+	var kwargs []KeywordArg
+
 	for _, arg := range node.Args {
-		evalArg, err := intr.Visit(arg, env)
-		if err != nil {
-			return nil, err
+		switch arg := arg.(type) {
+		case *parser.KeywordArgNode:
+			value, err := intr.Visit(arg.Value, env)
+			if err != nil {
+				return nil, err
+			}
+			kwargs = append(kwargs, KeywordArg{Name: arg.Name.Value, Value: value})
+		case *parser.SpreadNode:
+			value, err := intr.Visit(arg.Node, env)
+			if err != nil {
+				return nil, err
+			}
+
+			list, ok := value.(*ListValue)
+			if !ok {
+				return nil, utils.RuntimeError(fmt.Sprintf("Cannot spread '%s' into args", value.GetType()))
+			}
+			args = append(args, list.Elements...)
+		default:
+			evalArg, err := intr.Visit(arg, env)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, evalArg)
 		}
-		args = append(args, evalArg)
 	}
 
-	return funcToCall.Execute(env, args)
+	if kwargs == nil {
+		return funcToCall.Execute(env, args)
+	}
+
+	callable, ok := funcToCall.(KeywordCallable)
+	if !ok {
+		return nil, utils.RuntimeError(fmt.Sprintf("'%s' does not accept keyword args", funcToCall.Print()))
+	}
+
+	return callable.ExecuteWithKeywords(env, args, kwargs)
 }
 
 func (intr *Interpreter) visitStringNode(node *parser.StringNode) (RuntimeValue, error) {
@@ -261,6 +559,693 @@ func (intr *Interpreter) visitListNode(node *parser.ListNode, env *Environment)
 	return NewListValue(elements), nil
 }
 
+func (intr *Interpreter) visitListCompNode(node *parser.ListCompNode, env *Environment) (RuntimeValue, error) {
+	elements := make([]RuntimeValue, 0)
+
+	err := intr.runCompClauses(node.Clauses, NewEnvironment(env), func(compEnv *Environment) error {
+		el, err := intr.Visit(node.Element, compEnv)
+		if err != nil {
+			return err
+		}
+
+		elements = append(elements, el)
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewListValue(elements), nil
+}
+
+func (intr *Interpreter) visitMapCompNode(node *parser.MapCompNode, env *Environment) (RuntimeValue, error) {
+	m := NewMapValue()
+
+	err := intr.runCompClauses(node.Clauses, NewEnvironment(env), func(compEnv *Environment) error {
+		key, err := intr.Visit(node.Key, compEnv)
+		if err != nil {
+			return err
+		}
+
+		value, err := intr.Visit(node.Value, compEnv)
+		if err != nil {
+			return err
+		}
+
+		_, err = m.SetIndex(key, value)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// runCompClauses calls emit once for every combination of values produced
+// by the 'for' clauses that satisfies the 'if' clauses
+func (intr *Interpreter) runCompClauses(clauses []*parser.CompClause, env *Environment, emit func(env *Environment) error) error {
+	if len(clauses) == 0 {
+		return emit(env)
+	}
+
+	clause := clauses[0]
+
+	if clause.Condition != nil {
+		condition, err := intr.Visit(clause.Condition, env)
+		if err != nil {
+			return err
+		}
+
+		if !isTrue(condition) {
+			return nil
+		}
+
+		return intr.runCompClauses(clauses[1:], env, emit)
+	}
+
+	iterable, err := intr.Visit(clause.Iterable, env)
+	if err != nil {
+		return err
+	}
+
+	iterator, err := iterate(iterable, env)
+	if err != nil {
+		return err
+	}
+
+	for {
+		value, ok, err := iterator.Next()
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return nil
+		}
+
+		err = intr.destructure(clause.Pattern, value, env)
+		if err == nil {
+			err = intr.runCompClauses(clauses[1:], env, emit)
+		}
+
+		if err != nil {
+			finishIterator(iterator, err)
+			return err
+		}
+	}
+}
+
+func (intr *Interpreter) visitMapNode(node *parser.MapNode, env *Environment) (RuntimeValue, error) {
+	m := NewMapValue()
+
+	for i, k := range node.Keys {
+		key, err := intr.Visit(k, env)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := intr.Visit(node.Values[i], env)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := m.SetIndex(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+func (intr *Interpreter) visitInterpNode(node *parser.InterpNode, env *Environment) (RuntimeValue, error) {
+	var sb strings.Builder
+
+	for _, part := range node.Parts {
+		partVal, err := intr.Visit(part, env)
+		if err != nil {
+			return nil, err
+		}
+
+		if partVal != nil {
+			sb.WriteString(partVal.Print())
+		}
+	}
+
+	return NewStringValue(sb.String()), nil
+}
+
+func (intr *Interpreter) visitIndexNode(node *parser.IndexNode, env *Environment) (RuntimeValue, error) {
+	value, err := intr.Visit(node.Node, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if node.Optional && isNull(value) {
+		return nil, errShortCircuit
+	}
+
+	index, err := intr.Visit(node.Index, env)
+	if err != nil {
+		return nil, err
+	}
+
+	indexable, ok := value.(Indexable)
+	if !ok {
+		return nil, utils.RuntimeError("Illegal operation '[]'")
+	}
+
+	return indexable.GetIndex(index)
+}
+
+func (intr *Interpreter) visitSliceNode(node *parser.SliceNode, env *Environment) (RuntimeValue, error) {
+	value, err := intr.Visit(node.Node, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if node.Optional && isNull(value) {
+		return nil, errShortCircuit
+	}
+
+	bounds := make([]RuntimeValue, 0, 3)
+
+	for _, b := range []parser.AstNode{node.Start, node.End, node.Step} {
+		if b == nil {
+			bounds = append(bounds, nil)
+			continue
+		}
+
+		bound, err := intr.Visit(b, env)
+		if err != nil {
+			return nil, err
+		}
+		bounds = append(bounds, bound)
+	}
+
+	sliceable, ok := value.(Sliceable)
+	if !ok {
+		return nil, utils.RuntimeError("Illegal operation '[:]'")
+	}
+
+	return sliceable.Slice(bounds[0], bounds[1], bounds[2])
+}
+
+func (intr *Interpreter) visitIndexAssignNode(node *parser.IndexAssignNode, env *Environment) (RuntimeValue, error) {
+	value, err := intr.Visit(node.Node, env)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := intr.Visit(node.Index, env)
+	if err != nil {
+		return nil, err
+	}
+
+	newValue, err := intr.Visit(node.Value, env)
+	if err != nil {
+		return nil, err
+	}
+
+	indexable, ok := value.(Indexable)
+	if !ok {
+		return nil, utils.RuntimeError("Illegal operation '[]='")
+	}
+
+	return indexable.SetIndex(index, newValue)
+}
+
+func (intr *Interpreter) visitQuantityNode(node *parser.QuantityNode, env *Environment) (RuntimeValue, error) {
+	magnitude, err := intr.Visit(node.Node, env)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewQuantityValue(magnitude, node.Unit), nil
+}
+
+func (intr *Interpreter) visitConvertNode(node *parser.ConvertNode, env *Environment) (RuntimeValue, error) {
+	value, err := intr.Visit(node.Node, env)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity, ok := value.(*QuantityValue)
+	if !ok {
+		return nil, utils.RuntimeError(fmt.Sprintf("Cannot convert '%s' to '%s'", value.GetType(), node.Unit))
+	}
+
+	return quantity.To(node.Unit)
+}
+
+// assignTarget evaluates the parts of the target of a compound assignment
+// or an increment once, returning how to read and update its value
+func (intr *Interpreter) assignTarget(node parser.AstNode, env *Environment) (get func() (RuntimeValue, error), set func(value RuntimeValue) (RuntimeValue, error), err error) {
+	switch target := node.(type) {
+	case *parser.VarAccessNode:
+		get = func() (RuntimeValue, error) {
+			return env.Get(target.VarName.Value)
+		}
+		set = func(value RuntimeValue) (RuntimeValue, error) {
+			return value, env.Assign(target.VarName.Value, value)
+		}
+	case *parser.IndexNode:
+		value, err := intr.Visit(target.Node, env)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		index, err := intr.Visit(target.Index, env)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		indexable, ok := value.(Indexable)
+		if !ok {
+			return nil, nil, utils.RuntimeError("Illegal operation '[]='")
+		}
+
+		get = func() (RuntimeValue, error) {
+			return indexable.GetIndex(index)
+		}
+		set = func(value RuntimeValue) (RuntimeValue, error) {
+			return indexable.SetIndex(index, value)
+		}
+	case *parser.MemberAccessNode:
+		value, err := intr.Visit(target.Node, env)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		accessor, ok := value.(FieldAccessor)
+		if !ok {
+			return nil, nil, utils.RuntimeError(fmt.Sprintf("'%s' has no field '%s'", value.GetType(), target.Member.Value))
+		}
+
+		get = func() (RuntimeValue, error) {
+			return accessor.GetField(target.Member.Value)
+		}
+		set = func(value RuntimeValue) (RuntimeValue, error) {
+			return accessor.SetField(target.Member.Value, value)
+		}
+	default:
+		return nil, nil, utils.RuntimeError("Illegal assignment target")
+	}
+
+	return get, set, nil
+}
+
+// visitCompoundAssignNode reads the current value of the target, applies
+// the operator and stores the result
+func (intr *Interpreter) visitCompoundAssignNode(node *parser.CompoundAssignNode, env *Environment) (RuntimeValue, error) {
+	get, set, err := intr.assignTarget(node.Target, env)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := get()
+	if err != nil {
+		return nil, err
+	}
+
+	operand, err := intr.Visit(node.Value, env)
+	if err != nil {
+		return nil, err
+	}
+
+	var res RuntimeValue
+
+	switch node.Operator.Type {
+	case lexer.PlusEqualsTT:
+		res, err = current.Add(operand)
+	case lexer.MinusEqualsTT:
+		res, err = current.Subtract(operand)
+	case lexer.MultiplyEqualsTT:
+		res, err = current.Multiply(operand)
+	case lexer.DivideEqualsTT:
+		res, err = current.Divide(operand)
+	case lexer.ModEqualsTT:
+		res, err = current.Mod(operand)
+	case lexer.PowerEqualsTT:
+		res, err = current.Power(operand)
+	default:
+		return nil, utils.RuntimeError("Unsupported operation")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return set(res)
+}
+
+func (intr *Interpreter) visitIncrementNode(node *parser.IncrementNode, env *Environment) (RuntimeValue, error) {
+	get, set, err := intr.assignTarget(node.Target, env)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := get()
+	if err != nil {
+		return nil, err
+	}
+
+	var res RuntimeValue
+	if node.Operator.Type == lexer.DoublePlusTT {
+		res, err = current.Add(NewIntValue(1))
+	} else {
+		res, err = current.Subtract(NewIntValue(1))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := set(res); err != nil {
+		return nil, err
+	}
+
+	if node.Prefix {
+		return res, nil
+	}
+
+	return current, nil
+}
+
+func (intr *Interpreter) visitStructDefNode(node *parser.StructDefNode, env *Environment) (RuntimeValue, error) {
+	typeName := node.VarName.Value
+
+	fields := make([]string, 0)
+	for _, field := range node.Fields {
+		if slices.Contains(fields, field.Value) {
+			return nil, utils.RuntimeError(fmt.Sprintf("Duplicate field '%s' in '%s'", field.Value, typeName))
+		}
+		fields = append(fields, field.Value)
+	}
+
+	methods, err := intr.makeMethods(typeName, node.Methods, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	traits, err := intr.resolveTraits(node.Traits, env)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, trait := range traits {
+		err := trait.CheckConformance(typeName, func(name string) *FunctionValue {
+			return methods[name]
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	structType := NewStructTypeValue(typeName, fields, methods, traits)
+	env.Set(structType.Name, structType)
+
+	return structType, nil
+}
+
+func (intr *Interpreter) visitMemberAccessNode(node *parser.MemberAccessNode, env *Environment) (RuntimeValue, error) {
+	value, err := intr.Visit(node.Node, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if node.Optional && isNull(value) {
+		return nil, errShortCircuit
+	}
+
+	accessor, ok := value.(FieldAccessor)
+	if !ok {
+		return nil, utils.RuntimeError(fmt.Sprintf("'%s' has no field '%s'", value.GetType(), node.Member.Value))
+	}
+
+	return accessor.GetField(node.Member.Value)
+}
+
+func (intr *Interpreter) visitMemberAssignNode(node *parser.MemberAssignNode, env *Environment) (RuntimeValue, error) {
+	value, err := intr.Visit(node.Node, env)
+	if err != nil {
+		return nil, err
+	}
+
+	newValue, err := intr.Visit(node.Value, env)
+	if err != nil {
+		return nil, err
+	}
+
+	accessor, ok := value.(FieldAccessor)
+	if !ok {
+		return nil, utils.RuntimeError(fmt.Sprintf("'%s' has no field '%s'", value.GetType(), node.Member.Value))
+	}
+
+	return accessor.SetField(node.Member.Value, newValue)
+}
+
+func (intr *Interpreter) makeMethods(typeName string, nodes []*parser.FuncDefNode, owner *ClassValue) (map[string]*FunctionValue, error) {
+	methods := make(map[string]*FunctionValue)
+
+	for _, m := range nodes {
+		if _, found := methods[m.VarName.Value]; found {
+			return nil, utils.RuntimeError(fmt.Sprintf("Duplicate method '%s' in '%s'", m.VarName.Value, typeName))
+		}
+
+		method := intr.makeFunction(m)
+		method.Owner = owner
+		methods[method.Name] = method
+	}
+
+	return methods, nil
+}
+
+func (intr *Interpreter) resolveTraits(names []*lexer.Token, env *Environment) ([]*TraitValue, error) {
+	traits := make([]*TraitValue, 0)
+
+	for _, name := range names {
+		value, err := env.Get(name.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		trait, ok := value.(*TraitValue)
+		if !ok {
+			return nil, utils.RuntimeError(fmt.Sprintf("'%s' is not a trait", name.Value))
+		}
+		traits = append(traits, trait)
+	}
+
+	return traits, nil
+}
+
+func (intr *Interpreter) visitClassDefNode(node *parser.ClassDefNode, env *Environment) (RuntimeValue, error) {
+	var parent *ClassValue = nil
+
+	if node.Parent != nil {
+		parentValue, err := env.Get(node.Parent.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		parentClass, ok := parentValue.(*ClassValue)
+		if !ok {
+			return nil, utils.RuntimeError(fmt.Sprintf("'%s' is not a class", node.Parent.Value))
+		}
+		parent = parentClass
+	}
+
+	traits, err := intr.resolveTraits(node.Traits, env)
+	if err != nil {
+		return nil, err
+	}
+
+	class := NewClassValue(node.VarName.Value, parent, nil, traits)
+
+	class.Methods, err = intr.makeMethods(class.Name, node.Methods, class)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, trait := range traits {
+		if err := trait.CheckConformance(class.Name, class.FindMethod); err != nil {
+			return nil, err
+		}
+	}
+
+	env.Set(class.Name, class)
+
+	return class, nil
+}
+
+func (intr *Interpreter) visitTraitDefNode(node *parser.TraitDefNode, env *Environment) (RuntimeValue, error) {
+	methods := make([]*TraitMethodSpec, 0)
+
+	for _, m := range node.Methods {
+		argNames := make([]string, 0)
+		for _, arg := range m.Args {
+			argNames = append(argNames, arg.Value)
+		}
+
+		methods = append(methods, &TraitMethodSpec{Name: m.Name.Value, ArgNames: argNames})
+	}
+
+	trait := NewTraitValue(node.VarName.Value, methods)
+	env.Set(trait.Name, trait)
+
+	return trait, nil
+}
+
+func (intr *Interpreter) visitEnumDefNode(node *parser.EnumDefNode, env *Environment) (RuntimeValue, error) {
+	enum := NewEnumValue(node.VarName.Value)
+
+	for _, v := range node.Variants {
+		var fields []string = nil
+
+		if v.Fields != nil {
+			fields = make([]string, 0)
+			for _, field := range v.Fields {
+				if slices.Contains(fields, field.Value) {
+					return nil, utils.RuntimeError(fmt.Sprintf("Duplicate field '%s' in '%s'", field.Value, v.Name.Value))
+				}
+				fields = append(fields, field.Value)
+			}
+		}
+
+		if err := enum.AddVariant(v.Name.Value, fields); err != nil {
+			return nil, err
+		}
+	}
+
+	env.Set(enum.Name, enum)
+
+	return enum, nil
+}
+
+func (intr *Interpreter) visitMatchNode(node *parser.MatchNode, env *Environment) (RuntimeValue, error) {
+	subject, err := intr.Visit(node.Subject, env)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, arm := range node.Arms {
+		armEnv := NewEnvironment(env)
+
+		matched, err := intr.matchPattern(arm.Pattern, subject, armEnv)
+		if err != nil {
+			return nil, err
+		}
+
+		if !matched {
+			continue
+		}
+
+		if arm.Guard != nil {
+			guard, err := intr.Visit(arm.Guard, armEnv)
+			if err != nil {
+				return nil, err
+			}
+
+			if !isTrue(guard) {
+				continue
+			}
+		}
+
+		return intr.Visit(arm.Body, armEnv)
+	}
+
+	return nil, utils.RuntimeError(fmt.Sprintf("Non-exhaustive match: no arm matches '%s'", subject.Print()))
+}
+
+func (intr *Interpreter) visitThrowNode(node *parser.ThrowNode, env *Environment) (RuntimeValue, error) {
+	value, err := intr.Visit(node.Node, env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := value.(type) {
+	case *ErrorValue:
+		return nil, &ThrownError{Value: value}
+	case *StringValue:
+		return nil, &ThrownError{Value: NewErrorValue("Error", value.Value)}
+	}
+
+	return nil, utils.RuntimeError(fmt.Sprintf("Cannot throw '%s', expected an error or a string", value.GetType()))
+}
+
+// visitTryNode evaluates the body and, if it fails, the catch clause with
+// the error bound to the catch variable; the finally clause always runs and
+// an error raised by it replaces the outcome of the rest
+func (intr *Interpreter) visitTryNode(node *parser.TryNode, env *Environment) (RuntimeValue, error) {
+	res, err := intr.Visit(node.Body, env)
+
+	if err != nil && node.Catch != nil && !errors.Is(err, errGeneratorClosed) {
+		catchEnv := NewEnvironment(env)
+		catchEnv.Set(node.CatchVar.Value, errorValueOf(err))
+
+		res, err = intr.Visit(node.Catch, catchEnv)
+	}
+
+	if node.Finally != nil && !errors.Is(err, errGeneratorAbandoned) {
+		if _, finallyErr := intr.Visit(node.Finally, env); finallyErr != nil {
+			return nil, finallyErr
+		}
+	}
+
+	return res, err
+}
+
+// visitDeferNode schedules the expression to run when the enclosing
+// function call exits
+func (intr *Interpreter) visitDeferNode(node *parser.DeferNode, env *Environment) (RuntimeValue, error) {
+	frame := env.CallFrame()
+	if frame == nil {
+		return nil, utils.RuntimeError("'defer' outside of a function")
+	}
+
+	frame.deferred = append(frame.deferred, deferredExpr{node: node.Node, env: env})
+	return NewNullValue(), nil
+}
+
+// visitYieldNode hands a value to the consumer of the running generator
+func (intr *Interpreter) visitYieldNode(node *parser.YieldNode, env *Environment) (RuntimeValue, error) {
+	frame := env.CallFrame()
+	if frame == nil || frame.generator == nil {
+		return nil, utils.RuntimeError("'yield' outside of a generator")
+	}
+
+	value, err := intr.Visit(node.Node, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := frame.generator.yield(value); err != nil {
+		return nil, err
+	}
+
+	return NewNullValue(), nil
+}
+
+// runDeferred evaluates the deferred expressions of a call in LIFO order,
+// all of them run even if some fail, the first error is returned
+func (intr *Interpreter) runDeferred(frame *CallFrame) error {
+	var firstErr error
+
+	for i := len(frame.deferred) - 1; i >= 0; i-- {
+		deferred := frame.deferred[i]
+
+		if _, err := intr.Visit(deferred.node, deferred.env); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
 func (intr *Interpreter) Visit(node parser.AstNode, env *Environment) (RuntimeValue, error) {
 	switch node.GetType() {
 	case parser.NumberNT:
@@ -273,8 +1258,36 @@ func (intr *Interpreter) Visit(node parser.AstNode, env *Environment) (RuntimeVa
 		return intr.visitVarAccessNode(node.(*parser.VarAccessNode), env)
 	case parser.VarAssignNT:
 		return intr.visitVarAssignNode(node.(*parser.VarAssignNode), env)
+	case parser.QuantityNT:
+		return intr.visitQuantityNode(node.(*parser.QuantityNode), env)
+	case parser.ConvertNT:
+		return intr.visitConvertNode(node.(*parser.ConvertNode), env)
+	case parser.CompoundAssignNT:
+		return intr.visitCompoundAssignNode(node.(*parser.CompoundAssignNode), env)
+	case parser.IncrementNT:
+		return intr.visitIncrementNode(node.(*parser.IncrementNode), env)
+	case parser.OptionalChainNT:
+		return intr.visitOptionalChainNode(node.(*parser.OptionalChainNode), env)
+	case parser.RangeNT:
+		return intr.visitRangeNode(node.(*parser.RangeNode), env)
+	case parser.ListCompNT:
+		return intr.visitListCompNode(node.(*parser.ListCompNode), env)
+	case parser.MapCompNT:
+		return intr.visitMapCompNode(node.(*parser.MapCompNode), env)
+	case parser.YieldNT:
+		return intr.visitYieldNode(node.(*parser.YieldNode), env)
+	case parser.DeferNT:
+		return intr.visitDeferNode(node.(*parser.DeferNode), env)
+	case parser.ThrowNT:
+		return intr.visitThrowNode(node.(*parser.ThrowNode), env)
+	case parser.TryNT:
+		return intr.visitTryNode(node.(*parser.TryNode), env)
+	case parser.DestructureNT:
+		return intr.visitDestructureNode(node.(*parser.DestructureNode), env)
 	case parser.IfNT:
 		return intr.visitIfNode(node.(*parser.IfNode), env)
+	case parser.ForInNT:
+		return intr.visitForInNode(node.(*parser.ForInNode), env)
 	case parser.ForNT:
 		return intr.visitForNode(node.(*parser.ForNode), env)
 	case parser.WhileNT:
@@ -287,6 +1300,30 @@ func (intr *Interpreter) Visit(node parser.AstNode, env *Environment) (RuntimeVa
 		return intr.visitStringNode(node.(*parser.StringNode))
 	case parser.ListNT:
 		return intr.visitListNode(node.(*parser.ListNode), env)
+	case parser.MapNT:
+		return intr.visitMapNode(node.(*parser.MapNode), env)
+	case parser.StructDefNT:
+		return intr.visitStructDefNode(node.(*parser.StructDefNode), env)
+	case parser.MemberAccessNT:
+		return intr.visitMemberAccessNode(node.(*parser.MemberAccessNode), env)
+	case parser.MemberAssignNT:
+		return intr.visitMemberAssignNode(node.(*parser.MemberAssignNode), env)
+	case parser.ClassDefNT:
+		return intr.visitClassDefNode(node.(*parser.ClassDefNode), env)
+	case parser.TraitDefNT:
+		return intr.visitTraitDefNode(node.(*parser.TraitDefNode), env)
+	case parser.EnumDefNT:
+		return intr.visitEnumDefNode(node.(*parser.EnumDefNode), env)
+	case parser.MatchNT:
+		return intr.visitMatchNode(node.(*parser.MatchNode), env)
+	case parser.InterpNT:
+		return intr.visitInterpNode(node.(*parser.InterpNode), env)
+	case parser.IndexNT:
+		return intr.visitIndexNode(node.(*parser.IndexNode), env)
+	case parser.SliceNT:
+		return intr.visitSliceNode(node.(*parser.SliceNode), env)
+	case parser.IndexAssignNT:
+		return intr.visitIndexAssignNode(node.(*parser.IndexAssignNode), env)
 	default:
 		return nil, utils.RuntimeError("Unsupported node")
 	}
@@ -0,0 +1,236 @@
+package runtime
+
+import (
+	"fmt"
+	"go-interpreter/utils"
+)
+
+// Iterator produces the elements of a sequence one at a time, ok is false
+// once the sequence is exhausted
+type Iterator interface {
+	Next() (value RuntimeValue, ok bool, err error)
+}
+
+// Iterable is implemented by values that can be used in 'for x in ...'
+type Iterable interface {
+	Iter() (Iterator, error)
+}
+
+// sliceIterator iterates over a list, reading its elements as it goes so
+// in place updates made while iterating are seen
+type sliceIterator struct {
+	list  *ListValue
+	index int
+}
+
+func (it *sliceIterator) Next() (RuntimeValue, bool, error) {
+	if it.index >= len(it.list.Elements) {
+		return nil, false, nil
+	}
+
+	value := it.list.Elements[it.index]
+	it.index++
+
+	return value, true, nil
+}
+
+func (l *ListValue) Iter() (Iterator, error) {
+	return &sliceIterator{list: l}, nil
+}
+
+type runeIterator struct {
+	runes []rune
+	index int
+}
+
+func (it *runeIterator) Next() (RuntimeValue, bool, error) {
+	if it.index >= len(it.runes) {
+		return nil, false, nil
+	}
+
+	value := NewStringValue(string(it.runes[it.index]))
+	it.index++
+
+	return value, true, nil
+}
+
+func (s *StringValue) Iter() (Iterator, error) {
+	return &runeIterator{runes: []rune(s.Value)}, nil
+}
+
+// Iter iterates over the keys of the map, in insertion order
+func (m *MapValue) Iter() (Iterator, error) {
+	return &sliceIterator{list: NewListValue(m.Keys())}, nil
+}
+
+// methodIterator adapts a user defined iterator, a value with the
+// 'hasNext()' and 'next()' methods
+type methodIterator struct {
+	hasNext RuntimeValue
+	next    RuntimeValue
+	env     *Environment
+}
+
+func (it *methodIterator) Next() (RuntimeValue, bool, error) {
+	hasNext, err := it.hasNext.Execute(it.env, []RuntimeValue{})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !isTrue(hasNext) {
+		return nil, false, nil
+	}
+
+	value, err := it.next.Execute(it.env, []RuntimeValue{})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+// iterate returns an iterator over value, which is either a native
+// iterable or a value whose 'iter()' method returns an object with the
+// 'hasNext()' and 'next()' methods (or such an object itself)
+func iterate(value RuntimeValue, env *Environment) (Iterator, error) {
+	if iterable, ok := value.(Iterable); ok {
+		return iterable.Iter()
+	}
+
+	notIterable := utils.RuntimeError(fmt.Sprintf("'%s' is not iterable", value.GetType()))
+
+	accessor, ok := value.(FieldAccessor)
+	if !ok {
+		return nil, notIterable
+	}
+
+	if iter, err := accessor.GetField("iter"); err == nil {
+		iterator, err := iter.Execute(env, []RuntimeValue{})
+		if err != nil {
+			return nil, err
+		}
+
+		if iterator == value {
+			return methodIteratorOf(accessor, env, notIterable)
+		}
+
+		return iterate(iterator, env)
+	}
+
+	return methodIteratorOf(accessor, env, notIterable)
+}
+
+func methodIteratorOf(accessor FieldAccessor, env *Environment, notIterable error) (Iterator, error) {
+	hasNext, err := accessor.GetField("hasNext")
+	if err != nil {
+		return nil, notIterable
+	}
+
+	next, err := accessor.GetField("next")
+	if err != nil {
+		return nil, notIterable
+	}
+
+	return &methodIterator{hasNext: hasNext, next: next, env: env}, nil
+}
+
+// mapIterator lazily applies fn to each element of source
+type mapIterator struct {
+	source Iterator
+	fn     RuntimeValue
+	env    *Environment
+}
+
+func (it *mapIterator) Next() (RuntimeValue, bool, error) {
+	value, ok, err := it.source.Next()
+	if !ok || err != nil {
+		return nil, false, err
+	}
+
+	res, err := it.fn.Execute(it.env, []RuntimeValue{value})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return res, true, nil
+}
+
+func (it *mapIterator) Close() {
+	closeIterator(it.source)
+}
+
+// filterIterator lazily skips the elements of source for which fn is false
+type filterIterator struct {
+	source Iterator
+	fn     RuntimeValue
+	env    *Environment
+}
+
+func (it *filterIterator) Next() (RuntimeValue, bool, error) {
+	for {
+		value, ok, err := it.source.Next()
+		if !ok || err != nil {
+			return nil, false, err
+		}
+
+		keep, err := it.fn.Execute(it.env, []RuntimeValue{value})
+		if err != nil {
+			return nil, false, err
+		}
+
+		if isTrue(keep) {
+			return value, true, nil
+		}
+	}
+}
+
+func (it *filterIterator) Close() {
+	closeIterator(it.source)
+}
+
+// takeIterator stops after the first n elements of source, closing it
+type takeIterator struct {
+	source    Iterator
+	remaining int
+}
+
+func (it *takeIterator) Next() (RuntimeValue, bool, error) {
+	if it.remaining <= 0 {
+		closeIterator(it.source)
+		return nil, false, nil
+	}
+
+	it.remaining--
+	return it.source.Next()
+}
+
+func (it *takeIterator) Close() {
+	closeIterator(it.source)
+}
+
+// zipIterator produces lists holding one element of each source, it
+// stops as soon as one of the sources is exhausted
+type zipIterator struct {
+	sources []Iterator
+}
+
+func (it *zipIterator) Next() (RuntimeValue, bool, error) {
+	values := make([]RuntimeValue, 0, len(it.sources))
+
+	for _, source := range it.sources {
+		value, ok, err := source.Next()
+		if !ok || err != nil {
+			it.Close()
+			return nil, false, err
+		}
+		values = append(values, value)
+	}
+
+	return NewListValue(values), true, nil
+}
+
+func (it *zipIterator) Close() {
+	for _, source := range it.sources {
+		closeIterator(source)
+	}
+}
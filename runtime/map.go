@@ -0,0 +1,253 @@
+package runtime
+
+import (
+	"fmt"
+	"go-interpreter/utils"
+	"slices"
+)
+
+// Hashable is implemented by values that can be used as map keys
+type Hashable interface {
+	HashKey() HashKey
+}
+
+type HashKey struct {
+	Type  ValueType
+	Value any
+}
+
+func (nv *NumberValue) HashKey() HashKey {
+	return HashKey{Type: NumberVT, Value: nv.Value}
+}
+
+func (s *StringValue) HashKey() HashKey {
+	return HashKey{Type: StringVT, Value: s.Value}
+}
+
+func hashKeyOf(v RuntimeValue) (HashKey, error) {
+	hashable, ok := v.(Hashable)
+	if !ok {
+		return HashKey{}, utils.RuntimeError(fmt.Sprintf("Unhashable type '%s'", v.GetType()))
+	}
+
+	return hashable.HashKey(), nil
+}
+
+// valuesEqual reports whether two values compare equal with '==',
+// values that cannot be compared are considered different
+func valuesEqual(a, b RuntimeValue) bool {
+	res, err := a.Equals(b)
+	if err != nil {
+		return false
+	}
+
+	return isTrue(res)
+}
+
+// MapValue
+
+type MapEntry struct {
+	Key   RuntimeValue
+	Value RuntimeValue
+}
+
+type MapValue struct {
+	Type    ValueType
+	Entries map[HashKey]*MapEntry
+	Order   []HashKey
+}
+
+func NewMapValue() *MapValue {
+	return &MapValue{
+		Type:    MapVT,
+		Entries: make(map[HashKey]*MapEntry),
+		Order:   make([]HashKey, 0),
+	}
+}
+
+func (m *MapValue) GetType() ValueType {
+	return m.Type
+}
+
+func (m *MapValue) GetValue() any {
+	return m.Entries
+}
+
+func (m *MapValue) Print() string {
+	str := "{"
+
+	for i, k := range m.Order {
+		if i > 0 {
+			str += ", "
+		}
+		entry := m.Entries[k]
+		str += fmt.Sprintf("%s: %s", entry.Key.Print(), entry.Value.Print())
+	}
+
+	str += "}"
+	return str
+}
+
+func (m *MapValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '+'")
+}
+
+func (m *MapValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '-'")
+}
+
+func (m *MapValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '*'")
+}
+
+func (m *MapValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '/'")
+}
+
+func (m *MapValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '%'")
+}
+
+func (m *MapValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '^'")
+}
+
+func (m *MapValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	if m.Type != MapVT || other.GetType() != MapVT {
+		return nil, utils.RuntimeError("Illegal operation '=='")
+	}
+
+	return NewNumberValue(utils.BoolToNumber(m.equals(other.(*MapValue)))), nil
+}
+
+func (m *MapValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	if m.Type != MapVT || other.GetType() != MapVT {
+		return nil, utils.RuntimeError("Illegal operation '!='")
+	}
+
+	return NewNumberValue(utils.BoolToNumber(!m.equals(other.(*MapValue)))), nil
+}
+
+func (m *MapValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<'")
+}
+
+func (m *MapValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>'")
+}
+
+func (m *MapValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<='")
+}
+
+func (m *MapValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>='")
+}
+
+func (m *MapValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'and'")
+}
+
+func (m *MapValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'or'")
+}
+
+func (m *MapValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '()'")
+}
+
+func (m *MapValue) GetIndex(index RuntimeValue) (RuntimeValue, error) {
+	key, err := hashKeyOf(index)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, found := m.Entries[key]
+	if !found {
+		return nil, utils.RuntimeError(fmt.Sprintf("Key '%s' not found", index.Print()))
+	}
+
+	return entry.Value, nil
+}
+
+func (m *MapValue) SetIndex(index RuntimeValue, value RuntimeValue) (RuntimeValue, error) {
+	key, err := hashKeyOf(index)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry, found := m.Entries[key]; found {
+		entry.Value = value
+	} else {
+		m.Entries[key] = &MapEntry{Key: index, Value: value}
+		m.Order = append(m.Order, key)
+	}
+
+	return value, nil
+}
+
+func (m *MapValue) Has(index RuntimeValue) (bool, error) {
+	key, err := hashKeyOf(index)
+	if err != nil {
+		return false, err
+	}
+
+	_, found := m.Entries[key]
+	return found, nil
+}
+
+// Contains reports whether value is a key of the map
+func (m *MapValue) Contains(value RuntimeValue) (bool, error) {
+	return m.Has(value)
+}
+
+// Delete removes a key from the map and returns its value
+func (m *MapValue) Delete(index RuntimeValue) (RuntimeValue, error) {
+	value, err := m.GetIndex(index)
+	if err != nil {
+		return nil, err
+	}
+
+	key, _ := hashKeyOf(index)
+	delete(m.Entries, key)
+	m.Order = slices.DeleteFunc(m.Order, func(k HashKey) bool {
+		return k == key
+	})
+
+	return value, nil
+}
+
+func (m *MapValue) Keys() []RuntimeValue {
+	keys := make([]RuntimeValue, 0, len(m.Order))
+	for _, k := range m.Order {
+		keys = append(keys, m.Entries[k].Key)
+	}
+	return keys
+}
+
+func (m *MapValue) Values() []RuntimeValue {
+	values := make([]RuntimeValue, 0, len(m.Order))
+	for _, k := range m.Order {
+		values = append(values, m.Entries[k].Value)
+	}
+	return values
+}
+
+func (m *MapValue) Len() (int, error) {
+	return len(m.Order), nil
+}
+
+func (m *MapValue) equals(other *MapValue) bool {
+	if len(m.Order) != len(other.Order) {
+		return false
+	}
+
+	for key, entry := range m.Entries {
+		otherEntry, found := other.Entries[key]
+		if !found || !valuesEqual(entry.Value, otherEntry.Value) {
+			return false
+		}
+	}
+
+	return true
+}
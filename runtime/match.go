@@ -0,0 +1,342 @@
+package runtime
+
+import (
+	"fmt"
+	"go-interpreter/lexer"
+	"go-interpreter/parser"
+	"go-interpreter/utils"
+	"slices"
+)
+
+// matchPattern reports whether value matches the pattern, binding the
+// variables captured by the pattern in env
+func (intr *Interpreter) matchPattern(pattern parser.AstNode, value RuntimeValue, env *Environment) (bool, error) {
+	switch pattern.GetType() {
+	case parser.BindingPatternNT:
+		return intr.matchBindingPattern(pattern.(*parser.BindingPatternNode), value, env)
+	case parser.ConstructorPatternNT:
+		return intr.matchConstructorPattern(pattern.(*parser.ConstructorPatternNode), value, env)
+	case parser.LiteralPatternNT:
+		return intr.matchLiteralPattern(pattern.(*parser.LiteralPatternNode), value, env)
+	case parser.TypePatternNT:
+		return intr.matchTypePattern(pattern.(*parser.TypePatternNode), value, env)
+	case parser.ListPatternNT:
+		return intr.matchListPattern(pattern.(*parser.ListPatternNode), value, env)
+	case parser.MapPatternNT:
+		return intr.matchMapPattern(pattern.(*parser.MapPatternNode), value, env)
+	default:
+		return false, utils.RuntimeError("Unsupported pattern")
+	}
+}
+
+// matchBindingPattern matches anything, '_' binds nothing and the name of
+// a variant without payload of the value's own enum only matches that variant
+func (intr *Interpreter) matchBindingPattern(pattern *parser.BindingPatternNode, value RuntimeValue, env *Environment) (bool, error) {
+	name := pattern.VarName.Value
+
+	if name == "_" {
+		return true, nil
+	}
+
+	if variant, found := variantOf(value, name); found && isUnitVariant(variant) {
+		return valuesEqual(variant, value), nil
+	}
+
+	env.Set(name, value)
+	return true, nil
+}
+
+// resolvePath evaluates a dotted name such as 'Shape.Circle'
+func (intr *Interpreter) resolvePath(path []*lexer.Token, env *Environment) (RuntimeValue, error) {
+	target, err := env.Get(path[0].Value)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range path[1:] {
+		accessor, ok := target.(FieldAccessor)
+		if !ok {
+			return nil, utils.RuntimeError(fmt.Sprintf("'%s' has no field '%s'", target.GetType(), name.Value))
+		}
+
+		target, err = accessor.GetField(name.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return target, nil
+}
+
+// variantOf looks name up among the variants of the enum value belongs to
+func variantOf(value RuntimeValue, name string) (RuntimeValue, bool) {
+	s, ok := value.(*StructValue)
+	if !ok || s.StructType.Enum == nil {
+		return nil, false
+	}
+
+	variant, found := s.StructType.Enum.Variants[name]
+	return variant, found
+}
+
+// resolvePatternPath resolves the name used by a pattern matching value,
+// a bare name is first looked up among the variants of the value's own enum
+func (intr *Interpreter) resolvePatternPath(path []*lexer.Token, value RuntimeValue, env *Environment) (RuntimeValue, error) {
+	if variant, found := variantOf(value, path[0].Value); found && len(path) == 1 {
+		return variant, nil
+	}
+
+	return intr.resolvePath(path, env)
+}
+
+func (intr *Interpreter) matchConstructorPattern(pattern *parser.ConstructorPatternNode, value RuntimeValue, env *Environment) (bool, error) {
+	target, err := intr.resolvePatternPath(pattern.Path, value, env)
+	if err != nil {
+		return false, err
+	}
+
+	if isUnitVariant(target) {
+		if len(pattern.Args) > 0 {
+			return false, utils.RuntimeError(fmt.Sprintf("Variant '%s' has no fields", target.Print()))
+		}
+
+		return valuesEqual(target, value), nil
+	}
+
+	structType, ok := target.(*StructTypeValue)
+	if !ok {
+		return false, utils.RuntimeError(fmt.Sprintf("'%s' cannot be used as a pattern", target.Print()))
+	}
+
+	structValue, ok := value.(*StructValue)
+	if !ok || structValue.StructType != structType {
+		return false, nil
+	}
+
+	if pattern.Args == nil {
+		return true, nil
+	}
+
+	if len(pattern.Args) != len(structType.Fields) {
+		return false, utils.RuntimeError(fmt.Sprintf("Pattern '%s' expects %d fields, got %d", structType.Name, len(structType.Fields), len(pattern.Args)))
+	}
+
+	for i, arg := range pattern.Args {
+		matched, err := intr.matchPattern(arg, structValue.Fields[structType.Fields[i]], env)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// matchLiteralPattern compares numbers by value whatever their type, so
+// '2.0' matches the pattern '2'
+func (intr *Interpreter) matchLiteralPattern(pattern *parser.LiteralPatternNode, value RuntimeValue, env *Environment) (bool, error) {
+	literal, err := intr.Visit(pattern.Value, env)
+	if err != nil {
+		return false, err
+	}
+
+	sameType := literal.GetType() == value.GetType() || (isNumber(literal) && isNumber(value))
+	return sameType && valuesEqual(literal, value), nil
+}
+
+func (intr *Interpreter) matchTypePattern(pattern *parser.TypePatternNode, value RuntimeValue, env *Environment) (bool, error) {
+	matched, err := intr.isOfType(pattern.TypeName, value, env)
+	if err != nil || !matched {
+		return false, err
+	}
+
+	if pattern.VarName.Value != "_" {
+		env.Set(pattern.VarName.Value, value)
+	}
+
+	return true, nil
+}
+
+// builtinTypeNames can be used in type patterns without being defined,
+// Number matches numbers of any type
+var builtinTypeNames = []ValueType{NumberVT, IntVT, DecimalVT, RationalVT, ComplexVT, QuantityVT, StringVT, ListVT, MapVT, FuncVT, ErrorVT, IteratorVT, RangeVT, NullVT}
+
+// isOfType checks a value against a builtin type name, a struct, a class
+// (including subclasses), an enum or a trait
+func (intr *Interpreter) isOfType(typeName []*lexer.Token, value RuntimeValue, env *Environment) (bool, error) {
+	if len(typeName) == 1 && ValueType(typeName[0].Value) == NumberVT {
+		return isNumber(value), nil
+	} else if len(typeName) == 1 && slices.Contains(builtinTypeNames, ValueType(typeName[0].Value)) {
+		return value.GetType() == ValueType(typeName[0].Value), nil
+	}
+
+	target, err := intr.resolvePatternPath(typeName, value, env)
+	if err != nil {
+		return false, err
+	}
+
+	switch t := target.(type) {
+	case *StructTypeValue:
+		s, ok := value.(*StructValue)
+		return ok && s.StructType == t, nil
+	case *ClassValue:
+		i, ok := value.(*InstanceValue)
+		return ok && i.Class.IsSubclassOf(t), nil
+	case *EnumValue:
+		s, ok := value.(*StructValue)
+		return ok && s.StructType.Enum == t, nil
+	case *TraitValue:
+		i, ok := value.(TraitImplementer)
+		return ok && i.Implements(t), nil
+	}
+
+	return false, utils.RuntimeError(fmt.Sprintf("'%s' is not a type", target.Print()))
+}
+
+// checkListLength reports why a list of the given length is too short or
+// too long for the pattern, if it is
+func checkListLength(pattern *parser.ListPatternNode, length int) error {
+	fixed := len(pattern.Before) + len(pattern.After)
+
+	if pattern.Rest == nil && length != fixed {
+		return utils.RuntimeError(fmt.Sprintf("Expected %d values to unpack, got %d", fixed, length))
+	} else if length < fixed {
+		return utils.RuntimeError(fmt.Sprintf("Expected at least %d values to unpack, got %d", fixed, length))
+	}
+
+	return nil
+}
+
+func (intr *Interpreter) matchListPattern(pattern *parser.ListPatternNode, value RuntimeValue, env *Environment) (bool, error) {
+	list, ok := value.(*ListValue)
+	if !ok {
+		return false, nil
+	}
+
+	length := len(list.Elements)
+	if checkListLength(pattern, length) != nil {
+		return false, nil
+	}
+
+	for i, p := range pattern.Before {
+		matched, err := intr.matchPattern(p, list.Elements[i], env)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	for i, p := range pattern.After {
+		matched, err := intr.matchPattern(p, list.Elements[length-len(pattern.After)+i], env)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	if pattern.Rest != nil && pattern.Rest.Value != "_" {
+		rest := slices.Clone(list.Elements[len(pattern.Before) : length-len(pattern.After)])
+		env.Set(pattern.Rest.Value, NewListValue(rest))
+	}
+
+	return true, nil
+}
+
+func (intr *Interpreter) matchMapPattern(pattern *parser.MapPatternNode, value RuntimeValue, env *Environment) (bool, error) {
+	m, ok := value.(*MapValue)
+	if !ok {
+		return false, nil
+	}
+
+	for i, k := range pattern.Keys {
+		key, err := intr.Visit(k, env)
+		if err != nil {
+			return false, err
+		}
+
+		found, err := m.Has(key)
+		if err != nil || !found {
+			return false, err
+		}
+
+		entry, _ := m.GetIndex(key)
+
+		matched, err := intr.matchPattern(pattern.Values[i], entry, env)
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// destructure binds the variables of a 'var' pattern, unlike matchPattern
+// it reports why the value doesn't fit the pattern; nothing is bound in env
+// unless the whole pattern matches
+func (intr *Interpreter) destructure(pattern parser.AstNode, value RuntimeValue, env *Environment) error {
+	scratch := NewEnvironment(env)
+
+	matched, err := intr.matchPattern(pattern, value, scratch)
+	if err != nil {
+		return err
+	}
+
+	if !matched {
+		return intr.mismatchError(pattern, value, env)
+	}
+
+	for name, v := range scratch.variables {
+		env.Set(name, v)
+	}
+
+	return nil
+}
+
+// mismatchError explains why value doesn't match the pattern, looking for
+// the innermost part of the value at fault
+func (intr *Interpreter) mismatchError(pattern parser.AstNode, value RuntimeValue, env *Environment) error {
+	switch p := pattern.(type) {
+	case *parser.ListPatternNode:
+		list, ok := value.(*ListValue)
+		if !ok {
+			return utils.RuntimeError(fmt.Sprintf("Cannot destructure '%s' as a list", value.GetType()))
+		}
+
+		length := len(list.Elements)
+		if err := checkListLength(p, length); err != nil {
+			return err
+		}
+
+		elements := slices.Concat(list.Elements[:len(p.Before)], list.Elements[length-len(p.After):])
+		for i, sub := range slices.Concat(p.Before, p.After) {
+			if matched, err := intr.matchPattern(sub, elements[i], NewEnvironment(env)); err != nil || !matched {
+				return intr.mismatchError(sub, elements[i], env)
+			}
+		}
+	case *parser.MapPatternNode:
+		m, ok := value.(*MapValue)
+		if !ok {
+			return utils.RuntimeError(fmt.Sprintf("Cannot destructure '%s' as a map", value.GetType()))
+		}
+
+		for i, k := range p.Keys {
+			key, err := intr.Visit(k, env)
+			if err != nil {
+				return err
+			}
+
+			entry, err := m.GetIndex(key)
+			if err != nil {
+				return err
+			}
+
+			if matched, err := intr.matchPattern(p.Values[i], entry, NewEnvironment(env)); err != nil || !matched {
+				return intr.mismatchError(p.Values[i], entry, env)
+			}
+		}
+	}
+
+	return utils.RuntimeError(fmt.Sprintf("Cannot destructure '%s': value doesn't match the pattern", value.Print()))
+}
+
+func isUnitVariant(v RuntimeValue) bool {
+	s, ok := v.(*StructValue)
+	return ok && s.StructType.Enum != nil && s.StructType.Fields == nil
+}
@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"go-interpreter/utils"
+)
+
+// NullValue
+
+type NullValue struct {
+	Type ValueType
+}
+
+func NewNullValue() *NullValue {
+	return &NullValue{
+		Type: NullVT,
+	}
+}
+
+func (n *NullValue) GetType() ValueType {
+	return n.Type
+}
+
+func (n *NullValue) GetValue() any {
+	return nil
+}
+
+func (n *NullValue) Print() string {
+	return "null"
+}
+
+func (n *NullValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '+'")
+}
+
+func (n *NullValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '-'")
+}
+
+func (n *NullValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '*'")
+}
+
+func (n *NullValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '/'")
+}
+
+func (n *NullValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '%'")
+}
+
+func (n *NullValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '^'")
+}
+
+func (n *NullValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(isNull(other))), nil
+}
+
+func (n *NullValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(!isNull(other))), nil
+}
+
+func (n *NullValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<'")
+}
+
+func (n *NullValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>'")
+}
+
+func (n *NullValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<='")
+}
+
+func (n *NullValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>='")
+}
+
+func (n *NullValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'and'")
+}
+
+func (n *NullValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'or'")
+}
+
+func (n *NullValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '()'")
+}
+
+func (n *NullValue) HashKey() HashKey {
+	return HashKey{Type: NullVT}
+}
+
+func isNull(v RuntimeValue) bool {
+	return v == nil || v.GetType() == NullVT
+}
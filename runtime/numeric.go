@@ -0,0 +1,133 @@
+package runtime
+
+import (
+	"go-interpreter/units"
+	"go-interpreter/utils"
+	"math"
+	"math/big"
+)
+
+// numericRank orders the number types from the narrowest to the widest,
+// non numbers have rank 0
+func numericRank(v RuntimeValue) int {
+	switch v.(type) {
+	case *IntValue:
+		return 1
+	case *RationalValue:
+		return 2
+	case *NumberValue:
+		return 3
+	case *DecimalValue:
+		return 4
+	case *ComplexValue:
+		return 5
+	case *QuantityValue:
+		return 6
+	}
+
+	return 0
+}
+
+// coerce converts v to the type of other when other is a wider number,
+// so that mixed operations are carried out by the widest operand
+func coerce(v, other RuntimeValue) (RuntimeValue, bool) {
+	if numericRank(other) <= numericRank(v) {
+		return nil, false
+	}
+
+	switch other.(type) {
+	case *RationalValue:
+		r, err := toRational(v)
+		if err != nil {
+			return nil, false
+		}
+		return r, true
+	case *NumberValue:
+		f, _ := toFloat(v)
+		return NewNumberValue(f), true
+	case *DecimalValue:
+		d, err := toDecimal(v)
+		if err != nil {
+			return nil, false
+		}
+		return d, true
+	case *ComplexValue:
+		c, _ := toComplex(v)
+		return NewComplexValue(c), true
+	case *QuantityValue:
+		return NewQuantityValue(v, units.Unit{}), true
+	}
+
+	return nil, false
+}
+
+// toFloat returns the value of any real number as a float64
+func toFloat(v RuntimeValue) (float64, bool) {
+	switch n := v.(type) {
+	case *NumberValue:
+		return n.Value, true
+	case *IntValue:
+		return n.Float(), true
+	case *DecimalValue:
+		return n.Float(), true
+	case *RationalValue:
+		return n.Float(), true
+	}
+
+	return 0, false
+}
+
+// isNumber reports whether v is a number of any type
+func isNumber(v RuntimeValue) bool {
+	return numericRank(v) > 0
+}
+
+// isTrue reports whether a condition holds, true being any number equal to 1
+func isTrue(v RuntimeValue) bool {
+	f, ok := toFloat(v)
+	return ok && f == 1
+}
+
+// compareIntFloat compares an Int and a Number exactly, where rounding the
+// Int to a float64 would make 2^53 + 1 equal to 2^53, ok is false for any
+// other pair of operands
+func compareIntFloat(a, b RuntimeValue, op string) (RuntimeValue, bool) {
+	i, f, sign := a, b, 1
+	if _, isInt := a.(*IntValue); !isInt {
+		i, f, sign = b, a, -1
+	}
+
+	iv, ok := i.(*IntValue)
+	if !ok {
+		return nil, false
+	}
+
+	nv, ok := f.(*NumberValue)
+	if !ok || nv.Type != NumberVT {
+		return nil, false
+	}
+
+	if math.IsNaN(nv.Value) {
+		return NewNumberValue(utils.BoolToNumber(op == "!=")), true
+	}
+
+	cmp := sign * new(big.Float).SetInt(iv.big()).Cmp(big.NewFloat(nv.Value))
+
+	var res bool
+	switch op {
+	case "==":
+		res = cmp == 0
+	case "!=":
+		res = cmp != 0
+	case "<":
+		res = cmp < 0
+	case ">":
+		res = cmp > 0
+	case "<=":
+		res = cmp <= 0
+	case ">=":
+		res = cmp >= 0
+	}
+
+	return NewNumberValue(utils.BoolToNumber(res)), true
+}
@@ -0,0 +1,276 @@
+package runtime
+
+import (
+	"fmt"
+	"go-interpreter/units"
+	"go-interpreter/utils"
+)
+
+// QuantityValue
+
+// QuantityValue is a number with a unit, like '5 km' or '9.81 m/s^2'
+type QuantityValue struct {
+	Type      ValueType
+	Magnitude RuntimeValue
+	Unit      units.Unit
+}
+
+func NewQuantityValue(m RuntimeValue, u units.Unit) *QuantityValue {
+	return &QuantityValue{
+		Type:      QuantityVT,
+		Magnitude: m,
+		Unit:      u,
+	}
+}
+
+// newQuantity returns a plain number instead of a quantity whose unit
+// has no dimension, e.g. for '10 km / 2 m'
+func newQuantity(m RuntimeValue, u units.Unit) (RuntimeValue, error) {
+	if u.IsDimensionless() {
+		return scale(m, u.Factor())
+	}
+
+	return NewQuantityValue(m, u), nil
+}
+
+func scale(m RuntimeValue, factor float64) (RuntimeValue, error) {
+	if factor == 1 {
+		return m, nil
+	}
+
+	return m.Multiply(NewNumberValue(factor))
+}
+
+func describeUnit(u units.Unit) string {
+	if len(u) == 0 {
+		return "a plain number"
+	}
+
+	return fmt.Sprintf("'%s'", u)
+}
+
+// To converts the quantity to another unit of the same dimension
+func (q *QuantityValue) To(u units.Unit) (*QuantityValue, error) {
+	if q.Unit.Dimension() != u.Dimension() {
+		return nil, utils.RuntimeError(fmt.Sprintf("Cannot convert %s to %s", describeUnit(q.Unit), describeUnit(u)))
+	}
+
+	m, err := scale(q.Magnitude, q.Unit.Factor()/u.Factor())
+	if err != nil {
+		return nil, err
+	}
+
+	return NewQuantityValue(m, u), nil
+}
+
+// operand returns the right hand side of an operation as a quantity, plain
+// numbers being quantities without unit
+func (q *QuantityValue) operand(other RuntimeValue, op string) (*QuantityValue, error) {
+	if o, ok := other.(*QuantityValue); ok {
+		return o, nil
+	}
+
+	if !isNumber(other) {
+		return nil, utils.RuntimeError(fmt.Sprintf("Illegal operation '%s'", op))
+	}
+
+	return NewQuantityValue(other, units.Unit{}), nil
+}
+
+// aligned returns the magnitudes of both operands in the unit of q, they
+// must have the same dimension
+func (q *QuantityValue) aligned(other RuntimeValue, op string) (RuntimeValue, RuntimeValue, error) {
+	o, err := q.operand(other, op)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if q.Unit.Dimension() != o.Unit.Dimension() {
+		return nil, nil, utils.RuntimeError(fmt.Sprintf("Incompatible units for '%s': %s and %s", op, describeUnit(q.Unit), describeUnit(o.Unit)))
+	}
+
+	m, err := scale(o.Magnitude, o.Unit.Factor()/q.Unit.Factor())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return q.Magnitude, m, nil
+}
+
+func (q *QuantityValue) GetType() ValueType {
+	return q.Type
+}
+
+func (q *QuantityValue) GetValue() any {
+	return q.Magnitude.GetValue()
+}
+
+func (q *QuantityValue) Print() string {
+	return fmt.Sprintf("%s %s", q.Magnitude.Print(), q.Unit)
+}
+
+func (q *QuantityValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	a, b, err := q.aligned(other, "+")
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := a.Add(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return newQuantity(m, q.Unit)
+}
+
+func (q *QuantityValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	a, b, err := q.aligned(other, "-")
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := a.Subtract(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return newQuantity(m, q.Unit)
+}
+
+func (q *QuantityValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	o, err := q.operand(other, "*")
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := q.Magnitude.Multiply(o.Magnitude)
+	if err != nil {
+		return nil, err
+	}
+
+	return newQuantity(m, q.Unit.Mul(o.Unit))
+}
+
+func (q *QuantityValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	o, err := q.operand(other, "/")
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := q.Magnitude.Divide(o.Magnitude)
+	if err != nil {
+		return nil, err
+	}
+
+	return newQuantity(m, q.Unit.Div(o.Unit))
+}
+
+func (q *QuantityValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	a, b, err := q.aligned(other, "%")
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := a.Mod(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return newQuantity(m, q.Unit)
+}
+
+// Power only accepts integer exponents without unit
+func (q *QuantityValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	n, ok := toFloat(other)
+	if !ok || !utils.FloatIsInt(n) {
+		return nil, utils.RuntimeError(fmt.Sprintf("Exponents of quantities must be integers, got '%s'", other.Print()))
+	}
+
+	m, err := q.Magnitude.Power(other)
+	if err != nil {
+		return nil, err
+	}
+
+	return newQuantity(m, q.Unit.Pow(int(n)))
+}
+
+func (q *QuantityValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	a, b, err := q.aligned(other, "==")
+	if err != nil {
+		return nil, err
+	}
+
+	return a.Equals(b)
+}
+
+func (q *QuantityValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	a, b, err := q.aligned(other, "!=")
+	if err != nil {
+		return nil, err
+	}
+
+	return a.NotEquals(b)
+}
+
+func (q *QuantityValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	a, b, err := q.aligned(other, "<")
+	if err != nil {
+		return nil, err
+	}
+
+	return a.LessThan(b)
+}
+
+func (q *QuantityValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	a, b, err := q.aligned(other, ">")
+	if err != nil {
+		return nil, err
+	}
+
+	return a.GreaterThan(b)
+}
+
+func (q *QuantityValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	a, b, err := q.aligned(other, "<=")
+	if err != nil {
+		return nil, err
+	}
+
+	return a.LessThanEquals(b)
+}
+
+func (q *QuantityValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	a, b, err := q.aligned(other, ">=")
+	if err != nil {
+		return nil, err
+	}
+
+	return a.GreaterThanEquals(b)
+}
+
+func (q *QuantityValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'and'")
+}
+
+func (q *QuantityValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'or'")
+}
+
+func (q *QuantityValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '()'")
+}
+
+func (q *QuantityValue) GetField(name string) (RuntimeValue, error) {
+	switch name {
+	case "value":
+		return q.Magnitude, nil
+	case "unit":
+		return NewStringValue(q.Unit.String()), nil
+	}
+
+	return nil, utils.RuntimeError(fmt.Sprintf("'Quantity' has no field '%s'", name))
+}
+
+func (q *QuantityValue) SetField(name string, value RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Quantities are immutable")
+}
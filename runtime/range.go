@@ -0,0 +1,233 @@
+package runtime
+
+import (
+	"fmt"
+	"go-interpreter/utils"
+	"math"
+	"math/big"
+)
+
+// RangeValue
+
+// RangeValue is the lazy sequence built by 'a..b' and 'a..=b', its
+// elements are computed on demand from start, end and step
+type RangeValue struct {
+	Type      ValueType
+	Start     RuntimeValue
+	End       RuntimeValue
+	Step      RuntimeValue
+	Inclusive bool
+	Integer   bool     // elements are Ints, as start and step are
+	length    *big.Int // number of elements, computed exactly
+}
+
+// NewRangeValue expects finite real numbers as bounds and a step other than 0
+func NewRangeValue(s, e, st RuntimeValue, i bool) *RangeValue {
+	r := &RangeValue{
+		Type:      RangeVT,
+		Start:     s,
+		End:       e,
+		Step:      st,
+		Inclusive: i,
+	}
+	_, isIntStart := s.(*IntValue)
+	_, isIntStep := st.(*IntValue)
+	r.Integer = isIntStart && isIntStep
+	r.length = r.count()
+
+	return r
+}
+
+// count returns ceil((end - start) / step) for exclusive ranges, one more
+// than its floor for inclusive ones
+func (r *RangeValue) count() *big.Int {
+	start, _ := toRational(r.Start)
+	end, _ := toRational(r.End)
+	step, _ := toRational(r.Step)
+
+	span := new(big.Rat).Sub(end.Value, start.Value)
+	span.Quo(span, step.Value)
+	if span.Sign() < 0 {
+		return new(big.Int)
+	}
+
+	// span is not negative, so the truncated quotient is its floor
+	n := new(big.Int).Quo(span.Num(), span.Denom())
+	if r.Inclusive || !span.IsInt() {
+		return n.Add(n, big.NewInt(1))
+	}
+
+	return n
+}
+
+func (r *RangeValue) GetType() ValueType {
+	return r.Type
+}
+
+func (r *RangeValue) GetValue() any {
+	return [3]RuntimeValue{r.Start, r.End, r.Step}
+}
+
+func (r *RangeValue) Print() string {
+	op := ".."
+	if r.Inclusive {
+		op = "..="
+	}
+
+	str := fmt.Sprintf("%s%s%s", r.Start.Print(), op, r.End.Print())
+	if !isTrue(r.Step) {
+		str += fmt.Sprintf(" step %s", r.Step.Print())
+	}
+
+	return str
+}
+
+func (r *RangeValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '+'")
+}
+
+func (r *RangeValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '-'")
+}
+
+func (r *RangeValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '*'")
+}
+
+func (r *RangeValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '/'")
+}
+
+func (r *RangeValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '%'")
+}
+
+func (r *RangeValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '^'")
+}
+
+func (r *RangeValue) equals(other RuntimeValue) bool {
+	o, ok := other.(*RangeValue)
+	return ok && r.Inclusive == o.Inclusive && r.Integer == o.Integer &&
+		valuesEqual(r.Start, o.Start) && valuesEqual(r.End, o.End) && valuesEqual(r.Step, o.Step)
+}
+
+func (r *RangeValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(r.equals(other))), nil
+}
+
+func (r *RangeValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(!r.equals(other))), nil
+}
+
+func (r *RangeValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<'")
+}
+
+func (r *RangeValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>'")
+}
+
+func (r *RangeValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<='")
+}
+
+func (r *RangeValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>='")
+}
+
+func (r *RangeValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'and'")
+}
+
+func (r *RangeValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'or'")
+}
+
+func (r *RangeValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '()'")
+}
+
+// Len returns the number of elements of the range, which must fit in an int
+// for the range to be indexed
+func (r *RangeValue) Len() (int, error) {
+	if !r.length.IsInt64() || r.length.Int64() > math.MaxInt {
+		return 0, utils.RuntimeError(fmt.Sprintf("Range '%s' is too long", r.Print()))
+	}
+
+	return int(r.length.Int64()), nil
+}
+
+// at returns start + i * step, exactly for integer ranges
+func (r *RangeValue) at(i int64) RuntimeValue {
+	if r.Integer {
+		n := new(big.Int).Mul(big.NewInt(i), r.Step.(*IntValue).big())
+		return newBigIntValue(n.Add(n, r.Start.(*IntValue).big()))
+	}
+
+	start, _ := toFloat(r.Start)
+	step, _ := toFloat(r.Step)
+
+	return NewNumberValue(start + float64(i)*step)
+}
+
+func (r *RangeValue) GetIndex(index RuntimeValue) (RuntimeValue, error) {
+	length, err := r.Len()
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := resolveIndex(index, length)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.at(int64(i)), nil
+}
+
+func (r *RangeValue) SetIndex(index RuntimeValue, value RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Ranges are immutable")
+}
+
+// Contains reports whether value is one of the elements of the range
+func (r *RangeValue) Contains(value RuntimeValue) (bool, error) {
+	n, ok := toFloat(value)
+	if !ok || math.IsInf(n, 0) || math.IsNaN(n) {
+		return false, nil
+	}
+
+	v, _ := toRational(value)
+	start, _ := toRational(r.Start)
+	step, _ := toRational(r.Step)
+
+	i := new(big.Rat).Sub(v.Value, start.Value)
+	i.Quo(i, step.Value)
+
+	return i.IsInt() && i.Sign() >= 0 && i.Num().Cmp(r.length) < 0, nil
+}
+
+type rangeIterator struct {
+	rng    *RangeValue
+	index  int64
+	length int64
+}
+
+func (it *rangeIterator) Next() (RuntimeValue, bool, error) {
+	if it.index >= it.length {
+		return nil, false, nil
+	}
+
+	value := it.rng.at(it.index)
+	it.index++
+
+	return value, true, nil
+}
+
+func (r *RangeValue) Iter() (Iterator, error) {
+	length := int64(math.MaxInt64)
+	if r.length.IsInt64() {
+		length = r.length.Int64()
+	}
+
+	return &rangeIterator{rng: r, length: length}, nil
+}
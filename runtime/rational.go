@@ -0,0 +1,300 @@
+package runtime
+
+import (
+	"fmt"
+	"go-interpreter/utils"
+	"math"
+	"math/big"
+)
+
+// RationalValue
+
+// RationalValue is an exact fraction, built with 'rational(n, d)'
+type RationalValue struct {
+	Type  ValueType
+	Value *big.Rat
+}
+
+func NewRationalValue(r *big.Rat) *RationalValue {
+	return &RationalValue{
+		Type:  RationalVT,
+		Value: r,
+	}
+}
+
+// toRational converts Ints, Decimals, floats and strings like '1/3' to
+// an exact fraction
+func toRational(v RuntimeValue) (*RationalValue, error) {
+	switch n := v.(type) {
+	case *RationalValue:
+		return n, nil
+	case *IntValue:
+		return NewRationalValue(new(big.Rat).SetInt(n.big())), nil
+	case *DecimalValue:
+		r := new(big.Rat).SetInt(n.Coef)
+		if n.Scale >= 0 {
+			return NewRationalValue(r.Quo(r, new(big.Rat).SetInt(pow10(n.Scale)))), nil
+		}
+		return NewRationalValue(r.Mul(r, new(big.Rat).SetInt(pow10(-n.Scale)))), nil
+	case *NumberValue:
+		if math.IsInf(n.Value, 0) || math.IsNaN(n.Value) {
+			return nil, utils.RuntimeError(fmt.Sprintf("Cannot convert '%v' to a rational", n.Value))
+		}
+		return NewRationalValue(new(big.Rat).SetFloat64(n.Value)), nil
+	case *StringValue:
+		r, ok := new(big.Rat).SetString(n.Value)
+		if !ok {
+			return nil, utils.RuntimeError(fmt.Sprintf("Invalid rational '%s'", n.Value))
+		}
+		return NewRationalValue(r), nil
+	}
+
+	return nil, utils.RuntimeError(fmt.Sprintf("Cannot convert '%s' to a rational", v.GetType()))
+}
+
+func (r *RationalValue) Float() float64 {
+	f, _ := r.Value.Float64()
+	return f
+}
+
+func (r *RationalValue) GetType() ValueType {
+	return r.Type
+}
+
+func (r *RationalValue) GetValue() any {
+	return r.Value
+}
+
+func (r *RationalValue) Print() string {
+	return r.Value.RatString()
+}
+
+// operand returns the right hand side of an operation, after Ints were
+// converted, or fails
+func (r *RationalValue) operand(other RuntimeValue, op string) (*big.Rat, error) {
+	switch o := other.(type) {
+	case *RationalValue:
+		return o.Value, nil
+	case *IntValue:
+		return new(big.Rat).SetInt(o.big()), nil
+	}
+
+	return nil, utils.RuntimeError(fmt.Sprintf("Illegal operation '%s'", op))
+}
+
+func (r *RationalValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(r, other); ok {
+		return c.Add(other)
+	}
+
+	o, err := r.operand(other, "+")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRationalValue(new(big.Rat).Add(r.Value, o)), nil
+}
+
+func (r *RationalValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(r, other); ok {
+		return c.Subtract(other)
+	}
+
+	o, err := r.operand(other, "-")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRationalValue(new(big.Rat).Sub(r.Value, o)), nil
+}
+
+func (r *RationalValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(r, other); ok {
+		return c.Multiply(other)
+	}
+
+	o, err := r.operand(other, "*")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRationalValue(new(big.Rat).Mul(r.Value, o)), nil
+}
+
+func (r *RationalValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(r, other); ok {
+		return c.Divide(other)
+	}
+
+	o, err := r.operand(other, "/")
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Sign() == 0 {
+		return nil, utils.RuntimeError("Division by 0")
+	}
+
+	return NewRationalValue(new(big.Rat).Quo(r.Value, o)), nil
+}
+
+// Mod floors the quotient, like the '%' of Ints
+func (r *RationalValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(r, other); ok {
+		return c.Mod(other)
+	}
+
+	o, err := r.operand(other, "%")
+	if err != nil {
+		return nil, err
+	}
+
+	if o.Sign() == 0 {
+		return nil, utils.RuntimeError("Division by 0")
+	}
+
+	q := new(big.Rat).Quo(r.Value, o)
+	floor := new(big.Int).Div(q.Num(), q.Denom())
+	res := new(big.Rat).Mul(o, new(big.Rat).SetInt(floor))
+
+	return NewRationalValue(res.Sub(r.Value, res)), nil
+}
+
+// Power stays exact for integer exponents
+func (r *RationalValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	o, ok := other.(*IntValue)
+	if !ok || o.Big != nil {
+		return NewNumberValue(r.Float()).Power(other)
+	}
+
+	n := max(o.Value, -o.Value)
+	num := new(big.Int).Exp(r.Value.Num(), big.NewInt(n), nil)
+	den := new(big.Int).Exp(r.Value.Denom(), big.NewInt(n), nil)
+
+	if o.Value < 0 {
+		if num.Sign() == 0 {
+			return nil, utils.RuntimeError("Division by 0")
+		}
+		num, den = den, num
+	}
+
+	return NewRationalValue(new(big.Rat).SetFrac(num, den)), nil
+}
+
+func (r *RationalValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(r, other); ok {
+		return c.Equals(other)
+	}
+
+	o, err := r.operand(other, "==")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNumberValue(utils.BoolToNumber(r.Value.Cmp(o) == 0)), nil
+}
+
+func (r *RationalValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(r, other); ok {
+		return c.NotEquals(other)
+	}
+
+	o, err := r.operand(other, "!=")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNumberValue(utils.BoolToNumber(r.Value.Cmp(o) != 0)), nil
+}
+
+func (r *RationalValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(r, other); ok {
+		return c.LessThan(other)
+	}
+
+	o, err := r.operand(other, "<")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNumberValue(utils.BoolToNumber(r.Value.Cmp(o) < 0)), nil
+}
+
+func (r *RationalValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(r, other); ok {
+		return c.GreaterThan(other)
+	}
+
+	o, err := r.operand(other, ">")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNumberValue(utils.BoolToNumber(r.Value.Cmp(o) > 0)), nil
+}
+
+func (r *RationalValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(r, other); ok {
+		return c.LessThanEquals(other)
+	}
+
+	o, err := r.operand(other, "<=")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNumberValue(utils.BoolToNumber(r.Value.Cmp(o) <= 0)), nil
+}
+
+func (r *RationalValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	if c, ok := coerce(r, other); ok {
+		return c.GreaterThanEquals(other)
+	}
+
+	o, err := r.operand(other, ">=")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNumberValue(utils.BoolToNumber(r.Value.Cmp(o) >= 0)), nil
+}
+
+func (r *RationalValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(r.Float()).And(other)
+}
+
+func (r *RationalValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(r.Float()).Or(other)
+}
+
+func (r *RationalValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '()'")
+}
+
+func (r *RationalValue) GetField(name string) (RuntimeValue, error) {
+	switch name {
+	case "num":
+		return newBigIntValue(new(big.Int).Set(r.Value.Num())), nil
+	case "den":
+		return newBigIntValue(new(big.Int).Set(r.Value.Denom())), nil
+	}
+
+	return nil, utils.RuntimeError(fmt.Sprintf("'Rational' has no field '%s'", name))
+}
+
+func (r *RationalValue) SetField(name string, value RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Rationals are immutable")
+}
+
+// HashKey gives a fraction the key of the Int or Number it equals, if any
+func (r *RationalValue) HashKey() HashKey {
+	if r.Value.IsInt() {
+		return newBigIntValue(r.Value.Num()).HashKey()
+	}
+
+	if f, exact := r.Value.Float64(); exact {
+		return HashKey{Type: NumberVT, Value: f}
+	}
+
+	return HashKey{Type: RationalVT, Value: r.Print()}
+}
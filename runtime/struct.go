@@ -0,0 +1,283 @@
+package runtime
+
+import (
+	"fmt"
+	"go-interpreter/utils"
+	"slices"
+)
+
+// StructTypeValue
+
+type StructTypeValue struct {
+	Type    ValueType
+	Name    string
+	Fields  []string
+	Methods map[string]*FunctionValue
+	Traits  []*TraitValue
+	Enum    *EnumValue // enum the type is a variant of, if any
+}
+
+func NewStructTypeValue(n string, f []string, m map[string]*FunctionValue, t []*TraitValue) *StructTypeValue {
+	return &StructTypeValue{
+		Type:    StructTypeVT,
+		Name:    n,
+		Fields:  f,
+		Methods: m,
+		Traits:  t,
+	}
+}
+
+func (st *StructTypeValue) GetType() ValueType {
+	return st.Type
+}
+
+func (st *StructTypeValue) GetValue() any {
+	return st.Fields
+}
+
+func (st *StructTypeValue) Print() string {
+	if st.Enum != nil {
+		return fmt.Sprintf("<variant %s.%s>", st.Enum.Name, st.Name)
+	}
+
+	return fmt.Sprintf("<struct %s>", st.Name)
+}
+
+func (st *StructTypeValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '+'")
+}
+
+func (st *StructTypeValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '-'")
+}
+
+func (st *StructTypeValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '*'")
+}
+
+func (st *StructTypeValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '/'")
+}
+
+func (st *StructTypeValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '%'")
+}
+
+func (st *StructTypeValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '^'")
+}
+
+func (st *StructTypeValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(st == other)), nil
+}
+
+func (st *StructTypeValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(st != other)), nil
+}
+
+func (st *StructTypeValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<'")
+}
+
+func (st *StructTypeValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>'")
+}
+
+func (st *StructTypeValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<='")
+}
+
+func (st *StructTypeValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>='")
+}
+
+func (st *StructTypeValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'and'")
+}
+
+func (st *StructTypeValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'or'")
+}
+
+// Execute builds a new struct value, args are assigned to the fields in order
+func (st *StructTypeValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return st.ExecuteWithKeywords(parentEnv, args, nil)
+}
+
+// ExecuteWithKeywords builds a new struct value, keyword args are assigned
+// to the fields with the same name
+func (st *StructTypeValue) ExecuteWithKeywords(parentEnv *Environment, args []RuntimeValue, kwargs []KeywordArg) (RuntimeValue, error) {
+	if argsDiff := len(args) - len(st.Fields); argsDiff > 0 {
+		return nil, utils.RuntimeError(fmt.Sprintf("%d too many args passed into '%s'", argsDiff, st.Name))
+	}
+
+	fields := make(map[string]RuntimeValue)
+	for i, arg := range args {
+		fields[st.Fields[i]] = arg
+	}
+
+	for _, kwarg := range kwargs {
+		if !slices.Contains(st.Fields, kwarg.Name) {
+			return nil, utils.RuntimeError(fmt.Sprintf("'%s' has no field '%s'", st.Name, kwarg.Name))
+		}
+
+		if _, found := fields[kwarg.Name]; found {
+			return nil, utils.RuntimeError(fmt.Sprintf("Multiple values for field '%s' passed into '%s'", kwarg.Name, st.Name))
+		}
+
+		fields[kwarg.Name] = kwarg.Value
+	}
+
+	if missing := len(st.Fields) - len(fields); missing > 0 {
+		return nil, utils.RuntimeError(fmt.Sprintf("%d too few args passed into '%s'", missing, st.Name))
+	}
+
+	return NewStructValue(st, fields), nil
+}
+
+// StructValue
+
+type StructValue struct {
+	Type       ValueType
+	StructType *StructTypeValue
+	Fields     map[string]RuntimeValue
+}
+
+func NewStructValue(st *StructTypeValue, f map[string]RuntimeValue) *StructValue {
+	return &StructValue{
+		Type:       StructVT,
+		StructType: st,
+		Fields:     f,
+	}
+}
+
+func (s *StructValue) GetType() ValueType {
+	return s.Type
+}
+
+func (s *StructValue) GetValue() any {
+	return s.Fields
+}
+
+func (s *StructValue) Print() string {
+	if s.StructType.Enum != nil && s.StructType.Fields == nil {
+		return s.StructType.Name
+	}
+
+	str := s.StructType.Name + "("
+
+	for i, name := range s.StructType.Fields {
+		if i > 0 {
+			str += ", "
+		}
+		str += fmt.Sprintf("%s: %s", name, s.Fields[name].Print())
+	}
+
+	str += ")"
+	return str
+}
+
+func (s *StructValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '+'")
+}
+
+func (s *StructValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '-'")
+}
+
+func (s *StructValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '*'")
+}
+
+func (s *StructValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '/'")
+}
+
+func (s *StructValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '%'")
+}
+
+func (s *StructValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '^'")
+}
+
+func (s *StructValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	if s.Type != StructVT || other.GetType() != StructVT {
+		return nil, utils.RuntimeError("Illegal operation '=='")
+	}
+
+	return NewNumberValue(utils.BoolToNumber(s.equals(other.(*StructValue)))), nil
+}
+
+func (s *StructValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	if s.Type != StructVT || other.GetType() != StructVT {
+		return nil, utils.RuntimeError("Illegal operation '!='")
+	}
+
+	return NewNumberValue(utils.BoolToNumber(!s.equals(other.(*StructValue)))), nil
+}
+
+func (s *StructValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<'")
+}
+
+func (s *StructValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>'")
+}
+
+func (s *StructValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<='")
+}
+
+func (s *StructValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>='")
+}
+
+func (s *StructValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'and'")
+}
+
+func (s *StructValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'or'")
+}
+
+func (s *StructValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '()'")
+}
+
+// GetField returns the field with the given name or, if there is none,
+// the method with that name bound to the struct value
+func (s *StructValue) GetField(name string) (RuntimeValue, error) {
+	if value, found := s.Fields[name]; found {
+		return value, nil
+	}
+
+	if method, found := s.StructType.Methods[name]; found {
+		return method.Bind(s), nil
+	}
+
+	return nil, utils.RuntimeError(fmt.Sprintf("'%s' has no field '%s'", s.StructType.Name, name))
+}
+
+func (s *StructValue) SetField(name string, value RuntimeValue) (RuntimeValue, error) {
+	if !slices.Contains(s.StructType.Fields, name) {
+		return nil, utils.RuntimeError(fmt.Sprintf("'%s' has no field '%s'", s.StructType.Name, name))
+	}
+
+	s.Fields[name] = value
+	return value, nil
+}
+
+func (s *StructValue) equals(other *StructValue) bool {
+	if s.StructType != other.StructType {
+		return false
+	}
+
+	for name, value := range s.Fields {
+		if !valuesEqual(value, other.Fields[name]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,146 @@
+package runtime
+
+import (
+	"fmt"
+	"go-interpreter/utils"
+	"slices"
+)
+
+// TraitImplementer is implemented by types, and values of types, that
+// can declare conformance to traits
+type TraitImplementer interface {
+	Implements(trait *TraitValue) bool
+}
+
+// TraitValue
+
+type TraitMethodSpec struct {
+	Name     string
+	ArgNames []string
+}
+
+type TraitValue struct {
+	Type    ValueType
+	Name    string
+	Methods []*TraitMethodSpec
+}
+
+func NewTraitValue(n string, m []*TraitMethodSpec) *TraitValue {
+	return &TraitValue{
+		Type:    TraitVT,
+		Name:    n,
+		Methods: m,
+	}
+}
+
+func (t *TraitValue) GetType() ValueType {
+	return t.Type
+}
+
+func (t *TraitValue) GetValue() any {
+	return t.Methods
+}
+
+func (t *TraitValue) Print() string {
+	return fmt.Sprintf("<trait %s>", t.Name)
+}
+
+func (t *TraitValue) Add(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '+'")
+}
+
+func (t *TraitValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '-'")
+}
+
+func (t *TraitValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '*'")
+}
+
+func (t *TraitValue) Divide(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '/'")
+}
+
+func (t *TraitValue) Mod(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '%'")
+}
+
+func (t *TraitValue) Power(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '^'")
+}
+
+func (t *TraitValue) Equals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(t == other)), nil
+}
+
+func (t *TraitValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
+	return NewNumberValue(utils.BoolToNumber(t != other)), nil
+}
+
+func (t *TraitValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<'")
+}
+
+func (t *TraitValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>'")
+}
+
+func (t *TraitValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '<='")
+}
+
+func (t *TraitValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '>='")
+}
+
+func (t *TraitValue) And(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'and'")
+}
+
+func (t *TraitValue) Or(other RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation 'or'")
+}
+
+func (t *TraitValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Illegal operation '()'")
+}
+
+// CheckConformance verifies that findMethod resolves every method required
+// by the trait to a function that can be called with the same number of arguments
+func (t *TraitValue) CheckConformance(typeName string, findMethod func(name string) *FunctionValue) error {
+	for _, spec := range t.Methods {
+		method := findMethod(spec.Name)
+
+		if method == nil {
+			return utils.RuntimeError(fmt.Sprintf("'%s' does not implement '%s': missing method '%s'", typeName, t.Name, spec.Name))
+		}
+
+		if !method.AcceptsArgs(len(spec.ArgNames)) {
+			return utils.RuntimeError(fmt.Sprintf("'%s' does not implement '%s': method '%s' takes %d args, expected %d", typeName, t.Name, spec.Name, len(method.ArgNames), len(spec.ArgNames)))
+		}
+	}
+
+	return nil
+}
+
+func (st *StructTypeValue) Implements(trait *TraitValue) bool {
+	return slices.Contains(st.Traits, trait)
+}
+
+func (s *StructValue) Implements(trait *TraitValue) bool {
+	return s.StructType.Implements(trait)
+}
+
+func (c *ClassValue) Implements(trait *TraitValue) bool {
+	for class := c; class != nil; class = class.Parent {
+		if slices.Contains(class.Traits, trait) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (i *InstanceValue) Implements(trait *TraitValue) bool {
+	return i.Class.Implements(trait)
+}
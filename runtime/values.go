@@ -1,21 +1,40 @@
 package runtime
 
 import (
+	"errors"
 	"fmt"
 	"go-interpreter/parser"
 	"go-interpreter/utils"
 	"math"
-	"reflect"
+	"math/big"
 	"slices"
+	"strings"
 )
 
 type ValueType string
 
 const (
-	NumberVT ValueType = "Number"
-	FuncVT   ValueType = "Function"
-	StringVT ValueType = "String"
-	ListVT   ValueType = "List"
+	NumberVT     ValueType = "Number"
+	IntVT        ValueType = "Int"
+	DecimalVT    ValueType = "Decimal"
+	RationalVT   ValueType = "Rational"
+	ComplexVT    ValueType = "Complex"
+	QuantityVT   ValueType = "Quantity"
+	FuncVT       ValueType = "Function"
+	StringVT     ValueType = "String"
+	ListVT       ValueType = "List"
+	MapVT        ValueType = "Map"
+	StructTypeVT ValueType = "StructType"
+	StructVT     ValueType = "Struct"
+	ClassVT      ValueType = "Class"
+	InstanceVT   ValueType = "Instance"
+	SuperVT      ValueType = "Super"
+	TraitVT      ValueType = "Trait"
+	EnumVT       ValueType = "Enum"
+	ErrorVT      ValueType = "Error"
+	IteratorVT   ValueType = "Iterator"
+	RangeVT      ValueType = "Range"
+	NullVT       ValueType = "Null"
 )
 
 type RuntimeValue interface {
@@ -41,6 +60,149 @@ type RuntimeValue interface {
 	Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error)
 }
 
+// Indexable is implemented by values supporting 'a[i]' and 'a[i] = v'
+type Indexable interface {
+	GetIndex(index RuntimeValue) (RuntimeValue, error)
+	SetIndex(index RuntimeValue, value RuntimeValue) (RuntimeValue, error)
+}
+
+// FieldAccessor is implemented by values supporting 'a.b' and 'a.b = v'
+type FieldAccessor interface {
+	GetField(name string) (RuntimeValue, error)
+	SetField(name string, value RuntimeValue) (RuntimeValue, error)
+}
+
+// Sized is implemented by values whose length is returned by 'len', which
+// fails for lazy sequences too long to be indexed
+type Sized interface {
+	Len() (int, error)
+}
+
+// Sliceable is implemented by values supporting 'a[start:end:step]',
+// omitted bounds are passed as nil
+type Sliceable interface {
+	Slice(start, end, step RuntimeValue) (RuntimeValue, error)
+}
+
+// Container is implemented by values supporting membership tests with 'x in c'
+type Container interface {
+	Contains(value RuntimeValue) (bool, error)
+}
+
+// Integral is implemented by values supporting the integer operators '&',
+// '|', 'xor', '~', '<<', '>>' and '//'
+type Integral interface {
+	BitAnd(other RuntimeValue) (RuntimeValue, error)
+	BitOr(other RuntimeValue) (RuntimeValue, error)
+	BitXor(other RuntimeValue) (RuntimeValue, error)
+	BitNot() (RuntimeValue, error)
+	ShiftLeft(other RuntimeValue) (RuntimeValue, error)
+	ShiftRight(other RuntimeValue) (RuntimeValue, error)
+	FloorDivide(other RuntimeValue) (RuntimeValue, error) // rounds towards negative infinity, a // b * b + a % b == a
+}
+
+// KeywordArg is an argument passed by name, as in 'f(x, scale=2)'
+type KeywordArg struct {
+	Name  string
+	Value RuntimeValue
+}
+
+// KeywordCallable is implemented by callable values accepting keyword args
+type KeywordCallable interface {
+	ExecuteWithKeywords(parentEnv *Environment, args []RuntimeValue, kwargs []KeywordArg) (RuntimeValue, error)
+}
+
+// resolveIndex turns a (possibly negative) index into a position
+// inside a sequence of the given length
+func resolveIndex(index RuntimeValue, length int) (int, error) {
+	i, ok := toFloat(index)
+	if !ok {
+		return 0, utils.RuntimeError("Index must be a number")
+	}
+
+	if !utils.FloatIsInt(i) {
+		return 0, utils.RuntimeError("Index must be an integer")
+	}
+
+	pos := int(i)
+	if pos < 0 {
+		pos += length
+	}
+
+	if pos < 0 || pos >= length {
+		return 0, utils.RuntimeError("Index out of bounds")
+	}
+
+	return pos, nil
+}
+
+// sliceIndices returns the positions selected by a slice over a sequence
+// of the given length, following Python's slicing rules
+func sliceIndices(start, end, step RuntimeValue, length int) ([]int, error) {
+	st := 1
+
+	if step != nil {
+		v, err := sliceBound(step)
+		if err != nil {
+			return nil, err
+		}
+
+		if v == 0 {
+			return nil, utils.RuntimeError("Slice step cannot be 0")
+		}
+		st = v
+	}
+
+	lower, upper := 0, length
+	if st < 0 {
+		lower, upper = -1, length-1
+	}
+
+	clamp := func(i int) int {
+		if i < 0 {
+			i += length
+		}
+		return max(lower, min(i, upper))
+	}
+
+	from, to := lower, upper
+	if st < 0 {
+		from, to = upper, lower
+	}
+
+	if start != nil {
+		v, err := sliceBound(start)
+		if err != nil {
+			return nil, err
+		}
+		from = clamp(v)
+	}
+
+	if end != nil {
+		v, err := sliceBound(end)
+		if err != nil {
+			return nil, err
+		}
+		to = clamp(v)
+	}
+
+	indices := make([]int, 0)
+	for i := from; (st > 0 && i < to) || (st < 0 && i > to); i += st {
+		indices = append(indices, i)
+	}
+
+	return indices, nil
+}
+
+func sliceBound(v RuntimeValue) (int, error) {
+	f, ok := toFloat(v)
+	if !ok || !utils.FloatIsInt(f) {
+		return 0, utils.RuntimeError("Slice bounds must be integers")
+	}
+
+	return int(f), nil
+}
+
 // NumberValue
 
 type NumberValue struct {
@@ -68,119 +230,285 @@ func (nv *NumberValue) Print() string {
 }
 
 func (nv *NumberValue) Add(other RuntimeValue) (RuntimeValue, error) {
-	if nv.Type != NumberVT || other.GetType() != NumberVT {
+	if c, ok := coerce(nv, other); ok {
+		return c.Add(other)
+	}
+
+	o, ok := toFloat(other)
+	if nv.Type != NumberVT || !ok {
 		return nil, utils.RuntimeError("Illegal operation '+'")
 	}
 
-	return NewNumberValue(nv.Value + other.GetValue().(float64)), nil
+	return NewNumberValue(nv.Value + o), nil
 }
 
 func (nv *NumberValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
-	if nv.Type != NumberVT || other.GetType() != NumberVT {
+	if c, ok := coerce(nv, other); ok {
+		return c.Subtract(other)
+	}
+
+	o, ok := toFloat(other)
+	if nv.Type != NumberVT || !ok {
 		return nil, utils.RuntimeError("Illegal operation '-'")
 	}
 
-	return NewNumberValue(nv.Value - other.GetValue().(float64)), nil
+	return NewNumberValue(nv.Value - o), nil
 }
 
 func (nv *NumberValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
-	if nv.Type != NumberVT || other.GetType() != NumberVT {
+	if c, ok := coerce(nv, other); ok {
+		return c.Multiply(other)
+	}
+
+	o, ok := toFloat(other)
+	if nv.Type != NumberVT || !ok {
 		return nil, utils.RuntimeError("Illegal operation '*'")
 	}
 
-	return NewNumberValue(nv.Value * other.GetValue().(float64)), nil
+	return NewNumberValue(nv.Value * o), nil
 }
 
 func (nv *NumberValue) Divide(other RuntimeValue) (RuntimeValue, error) {
-	if nv.Type != NumberVT || other.GetType() != NumberVT {
+	if c, ok := coerce(nv, other); ok {
+		return c.Divide(other)
+	}
+
+	o, ok := toFloat(other)
+	if nv.Type != NumberVT || !ok {
 		return nil, utils.RuntimeError("Illegal operation '/'")
 	}
 
-	if other.GetValue().(float64) == 0.0 {
+	if o == 0.0 {
 		return nil, utils.RuntimeError("Division by 0")
 	}
 
-	return NewNumberValue(nv.Value / other.GetValue().(float64)), nil
+	return NewNumberValue(nv.Value / o), nil
 }
 
+// Mod takes the sign of the divisor, so that it agrees with '//'
 func (nv *NumberValue) Mod(other RuntimeValue) (RuntimeValue, error) {
-	if nv.Type != NumberVT || other.GetType() != NumberVT {
+	if c, ok := coerce(nv, other); ok {
+		return c.Mod(other)
+	}
+
+	o, ok := toFloat(other)
+	if nv.Type != NumberVT || !ok {
 		return nil, utils.RuntimeError("Illegal operation '%'")
 	}
 
-	return NewNumberValue(math.Mod(nv.Value, other.GetValue().(float64))), nil
+	res := math.Mod(nv.Value, o)
+	if res != 0 && (res < 0) != (o < 0) {
+		res += o
+	}
+
+	return NewNumberValue(res), nil
 }
 
 func (nv *NumberValue) Power(other RuntimeValue) (RuntimeValue, error) {
-	if nv.Type != NumberVT || other.GetType() != NumberVT {
+	if c, ok := coerce(nv, other); ok {
+		return c.Power(other)
+	}
+
+	o, ok := toFloat(other)
+	if nv.Type != NumberVT || !ok {
 		return nil, utils.RuntimeError("Illegal operation '*'")
 	}
 
-	return NewNumberValue(math.Pow(nv.Value, other.GetValue().(float64))), nil
+	return NewNumberValue(math.Pow(nv.Value, o)), nil
 }
 
 func (nv *NumberValue) Equals(other RuntimeValue) (RuntimeValue, error) {
-	if nv.Type != NumberVT || other.GetType() != NumberVT {
+	if res, ok := compareIntFloat(nv, other, "=="); ok {
+		return res, nil
+	}
+
+	if c, ok := coerce(nv, other); ok {
+		return c.Equals(other)
+	}
+
+	o, ok := toFloat(other)
+	if nv.Type != NumberVT || !ok {
 		return nil, utils.RuntimeError("Illegal operation '=='")
 	}
 
-	return NewNumberValue(utils.BoolToNumber(nv.Value == other.GetValue().(float64))), nil
+	return NewNumberValue(utils.BoolToNumber(nv.Value == o)), nil
 }
 
 func (nv *NumberValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
-	if nv.Type != NumberVT || other.GetType() != NumberVT {
+	if res, ok := compareIntFloat(nv, other, "!="); ok {
+		return res, nil
+	}
+
+	if c, ok := coerce(nv, other); ok {
+		return c.NotEquals(other)
+	}
+
+	o, ok := toFloat(other)
+	if nv.Type != NumberVT || !ok {
 		return nil, utils.RuntimeError("Illegal operation '!='")
 	}
 
-	return NewNumberValue(utils.BoolToNumber(nv.Value != other.GetValue().(float64))), nil
+	return NewNumberValue(utils.BoolToNumber(nv.Value != o)), nil
 }
 
 func (nv *NumberValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
-	if nv.Type != NumberVT || other.GetType() != NumberVT {
+	if res, ok := compareIntFloat(nv, other, "<"); ok {
+		return res, nil
+	}
+
+	if c, ok := coerce(nv, other); ok {
+		return c.LessThan(other)
+	}
+
+	o, ok := toFloat(other)
+	if nv.Type != NumberVT || !ok {
 		return nil, utils.RuntimeError("Illegal operation '<'")
 	}
 
-	return NewNumberValue(utils.BoolToNumber(nv.Value < other.GetValue().(float64))), nil
+	return NewNumberValue(utils.BoolToNumber(nv.Value < o)), nil
 }
 
 func (nv *NumberValue) GreaterThan(other RuntimeValue) (RuntimeValue, error) {
-	if nv.Type != NumberVT || other.GetType() != NumberVT {
+	if res, ok := compareIntFloat(nv, other, ">"); ok {
+		return res, nil
+	}
+
+	if c, ok := coerce(nv, other); ok {
+		return c.GreaterThan(other)
+	}
+
+	o, ok := toFloat(other)
+	if nv.Type != NumberVT || !ok {
 		return nil, utils.RuntimeError("Illegal operation '>'")
 	}
 
-	return NewNumberValue(utils.BoolToNumber(nv.Value > other.GetValue().(float64))), nil
+	return NewNumberValue(utils.BoolToNumber(nv.Value > o)), nil
 }
 
 func (nv *NumberValue) LessThanEquals(other RuntimeValue) (RuntimeValue, error) {
-	if nv.Type != NumberVT || other.GetType() != NumberVT {
+	if res, ok := compareIntFloat(nv, other, "<="); ok {
+		return res, nil
+	}
+
+	if c, ok := coerce(nv, other); ok {
+		return c.LessThanEquals(other)
+	}
+
+	o, ok := toFloat(other)
+	if nv.Type != NumberVT || !ok {
 		return nil, utils.RuntimeError("Illegal operation '<='")
 	}
 
-	return NewNumberValue(utils.BoolToNumber(nv.Value <= other.GetValue().(float64))), nil
+	return NewNumberValue(utils.BoolToNumber(nv.Value <= o)), nil
 }
 
 func (nv *NumberValue) GreaterThanEquals(other RuntimeValue) (RuntimeValue, error) {
-	if nv.Type != NumberVT || other.GetType() != NumberVT {
+	if res, ok := compareIntFloat(nv, other, ">="); ok {
+		return res, nil
+	}
+
+	if c, ok := coerce(nv, other); ok {
+		return c.GreaterThanEquals(other)
+	}
+
+	o, ok := toFloat(other)
+	if nv.Type != NumberVT || !ok {
 		return nil, utils.RuntimeError("Illegal operation '>='")
 	}
 
-	return NewNumberValue(utils.BoolToNumber(nv.Value >= other.GetValue().(float64))), nil
+	return NewNumberValue(utils.BoolToNumber(nv.Value >= o)), nil
 }
 
 func (nv *NumberValue) And(other RuntimeValue) (RuntimeValue, error) {
-	if nv.Type != NumberVT || other.GetType() != NumberVT {
+	o, ok := toFloat(other)
+	if nv.Type != NumberVT || !ok {
 		return nil, utils.RuntimeError("Illegal operation 'and'")
 	}
 
-	return NewNumberValue(utils.AndNumbers(nv.Value, other.GetValue().(float64))), nil
+	return NewNumberValue(utils.AndNumbers(nv.Value, o)), nil
 }
 
 func (nv *NumberValue) Or(other RuntimeValue) (RuntimeValue, error) {
-	if nv.Type != NumberVT || other.GetType() != NumberVT {
+	o, ok := toFloat(other)
+	if nv.Type != NumberVT || !ok {
 		return nil, utils.RuntimeError("Illegal operation 'or'")
 	}
 
-	return NewNumberValue(utils.OrNumbers(nv.Value, other.GetValue().(float64))), nil
+	return NewNumberValue(utils.OrNumbers(nv.Value, o)), nil
+}
+
+// integer converts the number to an Int for the integer operators,
+// failing if it has a fractional part
+func (nv *NumberValue) integer(op string) (*IntValue, error) {
+	if nv.Value != math.Trunc(nv.Value) || math.IsInf(nv.Value, 0) {
+		return nil, utils.RuntimeError(fmt.Sprintf("Operands of '%s' must be integers, got '%v'", op, nv.Value))
+	}
+
+	b, _ := big.NewFloat(nv.Value).Int(nil)
+	return newBigIntValue(b), nil
+}
+
+func (nv *NumberValue) BitAnd(other RuntimeValue) (RuntimeValue, error) {
+	i, err := nv.integer("&")
+	if err != nil {
+		return nil, err
+	}
+
+	return i.BitAnd(other)
+}
+
+func (nv *NumberValue) BitOr(other RuntimeValue) (RuntimeValue, error) {
+	i, err := nv.integer("|")
+	if err != nil {
+		return nil, err
+	}
+
+	return i.BitOr(other)
+}
+
+func (nv *NumberValue) BitXor(other RuntimeValue) (RuntimeValue, error) {
+	i, err := nv.integer("xor")
+	if err != nil {
+		return nil, err
+	}
+
+	return i.BitXor(other)
+}
+
+func (nv *NumberValue) BitNot() (RuntimeValue, error) {
+	i, err := nv.integer("~")
+	if err != nil {
+		return nil, err
+	}
+
+	return i.BitNot()
+}
+
+func (nv *NumberValue) ShiftLeft(other RuntimeValue) (RuntimeValue, error) {
+	i, err := nv.integer("<<")
+	if err != nil {
+		return nil, err
+	}
+
+	return i.ShiftLeft(other)
+}
+
+func (nv *NumberValue) ShiftRight(other RuntimeValue) (RuntimeValue, error) {
+	i, err := nv.integer(">>")
+	if err != nil {
+		return nil, err
+	}
+
+	return i.ShiftRight(other)
+}
+
+func (nv *NumberValue) FloorDivide(other RuntimeValue) (RuntimeValue, error) {
+	i, err := nv.integer("//")
+	if err != nil {
+		return nil, err
+	}
+
+	return i.FloorDivide(other)
 }
 
 func (nv *NumberValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
@@ -190,10 +518,15 @@ func (nv *NumberValue) Execute(parentEnv *Environment, args []RuntimeValue) (Run
 // FuncValue
 
 type FunctionValue struct {
-	Type     ValueType
-	Name     string
-	Body     parser.AstNode
-	ArgNames []string
+	Type        ValueType
+	Name        string
+	Body        parser.AstNode
+	ArgNames    []string
+	Defaults    []parser.AstNode // default value of each arg, nil if it has none
+	RestArg     string           // name collecting the extra positional args, if any
+	IsGenerator bool
+	Owner       *ClassValue  // class defining the function, for methods
+	Self        RuntimeValue // value bound to 'self', for bound methods
 }
 
 func NewFunctionValue(n string, b parser.AstNode, a []string) *FunctionValue {
@@ -205,6 +538,13 @@ func NewFunctionValue(n string, b parser.AstNode, a []string) *FunctionValue {
 	}
 }
 
+// Bind returns a copy of the method with 'self' bound to the given value
+func (f *FunctionValue) Bind(self RuntimeValue) *FunctionValue {
+	bound := *f
+	bound.Self = self
+	return &bound
+}
+
 func (f *FunctionValue) GetType() ValueType {
 	return f.Type
 }
@@ -274,24 +614,135 @@ func (f *FunctionValue) Or(other RuntimeValue) (RuntimeValue, error) {
 }
 
 func (f *FunctionValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
+	return f.ExecuteWithKeywords(parentEnv, args, nil)
+}
+
+func (f *FunctionValue) ExecuteWithKeywords(parentEnv *Environment, args []RuntimeValue, kwargs []KeywordArg) (RuntimeValue, error) {
 	intr := NewInterpreter()
 	env := NewEnvironment(parentEnv)
+	env.frame = &CallFrame{}
+
+	if err := f.bindArgs(intr, env, args, kwargs); err != nil {
+		return nil, err
+	}
+
+	if f.Self != nil {
+		env.Set("self", f.Self)
+
+		if f.Owner != nil && f.Owner.Parent != nil {
+			env.Set("super", NewSuperValue(f.Self, f.Owner.Parent))
+		}
+	}
+
+	if f.IsGenerator {
+		return NewIteratorValue(fmt.Sprintf("generator %s", f.Name), newGenerator(f, intr, env)), nil
+	}
+
+	return f.run(intr, env)
+}
+
+// run evaluates the body in the environment prepared by the call
+func (f *FunctionValue) run(intr *Interpreter, env *Environment) (RuntimeValue, error) {
+	res, err := intr.Visit(f.Body, env)
+
+	// an error raised by a deferred expression is only reported when the
+	// body itself succeeded
+	if errors.Is(err, errGeneratorAbandoned) {
+		return nil, err
+	} else if deferErr := intr.runDeferred(env.frame); err == nil {
+		err = deferErr
+	}
+
+	if errors.Is(err, errGeneratorClosed) {
+		return nil, err
+	} else if err != nil {
+		return nil, withFrame(err, f.Name)
+	}
+
+	return res, nil
+}
 
+// bindArgs assigns positional and keyword args to the parameters, missing
+// args take their default value, evaluated after the preceding parameters
+func (f *FunctionValue) bindArgs(intr *Interpreter, env *Environment, args []RuntimeValue, kwargs []KeywordArg) error {
 	argsDiff := len(args) - len(f.ArgNames)
 
-	if argsDiff > 0 {
-		return nil, utils.RuntimeError(fmt.Sprintf("%d too many args passed into '%s'", argsDiff, f.Name))
-	} else if argsDiff < 0 {
-		return nil, utils.RuntimeError(fmt.Sprintf("%d too few args passed into '%s'", argsDiff*-1, f.Name))
+	if argsDiff > 0 && f.RestArg == "" {
+		return utils.RuntimeError(fmt.Sprintf("%d too many args passed into '%s'", argsDiff, f.Name))
 	}
 
+	bound := make(map[string]RuntimeValue)
+
 	for i, arg := range args {
-		argName := f.ArgNames[i]
-		argValue := arg
-		env.Set(argName, argValue)
+		if i < len(f.ArgNames) {
+			bound[f.ArgNames[i]] = arg
+		}
+	}
+
+	for _, kwarg := range kwargs {
+		if !slices.Contains(f.ArgNames, kwarg.Name) {
+			return utils.RuntimeError(fmt.Sprintf("Unknown keyword arg '%s' passed into '%s'", kwarg.Name, f.Name))
+		}
+
+		if _, found := bound[kwarg.Name]; found {
+			return utils.RuntimeError(fmt.Sprintf("Multiple values for arg '%s' passed into '%s'", kwarg.Name, f.Name))
+		}
+
+		bound[kwarg.Name] = kwarg.Value
 	}
 
-	return intr.Visit(f.Body, env)
+	missing := 0
+	for i, argName := range f.ArgNames {
+		if _, found := bound[argName]; !found && (f.Defaults == nil || f.Defaults[i] == nil) {
+			missing++
+		}
+	}
+
+	if missing > 0 {
+		return utils.RuntimeError(fmt.Sprintf("%d too few args passed into '%s'", missing, f.Name))
+	}
+
+	for i, argName := range f.ArgNames {
+		value, found := bound[argName]
+
+		if !found {
+			var err error
+			value, err = intr.Visit(f.Defaults[i], env)
+			if err != nil {
+				return err
+			}
+		}
+
+		env.Set(argName, value)
+	}
+
+	if f.RestArg != "" {
+		rest := make([]RuntimeValue, 0)
+		if argsDiff > 0 {
+			rest = append(rest, args[len(f.ArgNames):]...)
+		}
+
+		env.Set(f.RestArg, NewListValue(rest))
+	}
+
+	return nil
+}
+
+// MinArgs returns the number of args that must be passed to the function
+func (f *FunctionValue) MinArgs() int {
+	count := 0
+	for i := range f.ArgNames {
+		if f.Defaults == nil || f.Defaults[i] == nil {
+			count++
+		}
+	}
+
+	return count
+}
+
+// AcceptsArgs reports whether the function can be called with n positional args
+func (f *FunctionValue) AcceptsArgs(n int) bool {
+	return n >= f.MinArgs() && (n <= len(f.ArgNames) || f.RestArg != "")
 }
 
 // StringValue
@@ -333,12 +784,13 @@ func (s *StringValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
 }
 
 func (s *StringValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
-	if s.Type != StringVT || other.GetType() != NumberVT {
+	n, ok := toFloat(other)
+	if s.Type != StringVT || !ok {
 		return nil, utils.RuntimeError("Illegal operation '*'")
 	}
 
 	finalStr := ""
-	times := int(other.GetValue().(float64))
+	times := int(n)
 
 	for i := 0; i < times; i++ {
 		finalStr += s.Value
@@ -403,6 +855,51 @@ func (s *StringValue) Execute(parentEnv *Environment, args []RuntimeValue) (Runt
 	return nil, utils.RuntimeError("Illegal operation '()'")
 }
 
+func (s *StringValue) GetIndex(index RuntimeValue) (RuntimeValue, error) {
+	runes := []rune(s.Value)
+
+	i, err := resolveIndex(index, len(runes))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStringValue(string(runes[i])), nil
+}
+
+// Contains reports whether value is a substring of the string
+func (s *StringValue) Contains(value RuntimeValue) (bool, error) {
+	sub, ok := value.(*StringValue)
+	if !ok {
+		return false, utils.RuntimeError(fmt.Sprintf("Cannot look for '%s' in a string", value.GetType()))
+	}
+
+	return strings.Contains(s.Value, sub.Value), nil
+}
+
+func (s *StringValue) Len() (int, error) {
+	return len([]rune(s.Value)), nil
+}
+
+func (s *StringValue) SetIndex(index RuntimeValue, value RuntimeValue) (RuntimeValue, error) {
+	return nil, utils.RuntimeError("Strings are immutable")
+}
+
+func (s *StringValue) Slice(start, end, step RuntimeValue) (RuntimeValue, error) {
+	runes := []rune(s.Value)
+
+	indices, err := sliceIndices(start, end, step, len(runes))
+	if err != nil {
+		return nil, err
+	}
+
+	sliced := make([]rune, 0, len(indices))
+	for _, i := range indices {
+		sliced = append(sliced, runes[i])
+	}
+
+	return NewStringValue(string(sliced)), nil
+}
+
 // ListValue
 
 type ListValue struct {
@@ -443,41 +940,27 @@ func (l *ListValue) Print() string {
 
 // append a single element to a list
 func (l *ListValue) Add(other RuntimeValue) (RuntimeValue, error) {
-	if l.Type != ListVT || other.GetType() != NumberVT {
+	if l.Type != ListVT || !isNumber(other) {
 		return nil, utils.RuntimeError("Illegal operation '+'")
 	}
 
-	return NewListValue(append(l.Elements, other)), nil
+	return NewListValue(slices.Concat(l.Elements, []RuntimeValue{other})), nil
 }
 
 // remove element at index other.Value from list
 func (l *ListValue) Subtract(other RuntimeValue) (RuntimeValue, error) {
-	if l.Type != ListVT || other.GetType() != NumberVT {
+	if l.Type != ListVT || !isNumber(other) {
 		return nil, utils.RuntimeError("Illegal operation '-'")
 	}
 
-	index := other.GetValue().(float64)
-
-	if !utils.FloatIsInt(index) {
-		return nil, utils.RuntimeError("Index must be an integer")
-	}
-
-	absIndex := int(math.Abs(index))
-	length := len(l.Elements)
-
-	if absIndex > length {
-		return nil, utils.RuntimeError("Index out of bounds")
+	index, err := resolveIndex(other, len(l.Elements))
+	if err != nil {
+		return nil, err
 	}
 
-	elementsCopy := make([]RuntimeValue, len(l.Elements))
-	copy(elementsCopy, l.Elements)
-	var newElements []RuntimeValue
-
-	if index >= 0 {
-		newElements = append(elementsCopy[:absIndex], elementsCopy[absIndex+1:]...)
-	} else {
-		newElements = append(elementsCopy[:length-absIndex], elementsCopy[length-absIndex+1:]...)
-	}
+	newElements := make([]RuntimeValue, 0, len(l.Elements)-1)
+	newElements = append(newElements, l.Elements[:index]...)
+	newElements = append(newElements, l.Elements[index+1:]...)
 
 	return NewListValue(newElements), nil
 }
@@ -493,28 +976,11 @@ func (l *ListValue) Multiply(other RuntimeValue) (RuntimeValue, error) {
 
 // get element at index other.Value
 func (l *ListValue) Divide(other RuntimeValue) (RuntimeValue, error) {
-	if l.Type != ListVT || other.GetType() != NumberVT {
+	if l.Type != ListVT || !isNumber(other) {
 		return nil, utils.RuntimeError("Illegal operation '/'")
 	}
 
-	index := other.GetValue().(float64)
-
-	if index != float64(int(index)) {
-		return nil, utils.RuntimeError("Index must be an integer")
-	}
-
-	absIndex := int(math.Abs(index))
-	length := len(l.Elements)
-
-	if absIndex > length {
-		return nil, utils.RuntimeError("Index out of bounds")
-	}
-
-	if index >= 0 {
-		return l.Elements[absIndex], nil
-	} else {
-		return l.Elements[length-absIndex], nil
-	}
+	return l.GetIndex(other)
 }
 
 func (l *ListValue) Mod(other RuntimeValue) (RuntimeValue, error) {
@@ -525,12 +991,17 @@ func (l *ListValue) Power(other RuntimeValue) (RuntimeValue, error) {
 	return nil, utils.RuntimeError("Illegal operation '^'")
 }
 
+// equals compares the lists element by element with '=='
+func (l *ListValue) equals(other *ListValue) bool {
+	return slices.EqualFunc(l.Elements, other.Elements, valuesEqual)
+}
+
 func (l *ListValue) Equals(other RuntimeValue) (RuntimeValue, error) {
 	if l.Type != ListVT || other.GetType() != ListVT {
 		return nil, utils.RuntimeError("Illegal operation '=='")
 	}
 
-	return NewNumberValue(utils.BoolToNumber(reflect.DeepEqual(l.Elements, other.(*ListValue).Elements))), nil
+	return NewNumberValue(utils.BoolToNumber(l.equals(other.(*ListValue)))), nil
 }
 
 func (l *ListValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
@@ -538,7 +1009,7 @@ func (l *ListValue) NotEquals(other RuntimeValue) (RuntimeValue, error) {
 		return nil, utils.RuntimeError("Illegal operation '!='")
 	}
 
-	return NewNumberValue(utils.BoolToNumber(!reflect.DeepEqual(l.Elements, other.(*ListValue).Elements))), nil
+	return NewNumberValue(utils.BoolToNumber(!l.equals(other.(*ListValue)))), nil
 }
 
 func (l *ListValue) LessThan(other RuntimeValue) (RuntimeValue, error) {
@@ -568,3 +1039,50 @@ func (l *ListValue) Or(other RuntimeValue) (RuntimeValue, error) {
 func (l *ListValue) Execute(parentEnv *Environment, args []RuntimeValue) (RuntimeValue, error) {
 	return nil, utils.RuntimeError("Illegal operation '()'")
 }
+
+func (l *ListValue) GetIndex(index RuntimeValue) (RuntimeValue, error) {
+	i, err := resolveIndex(index, len(l.Elements))
+	if err != nil {
+		return nil, err
+	}
+
+	return l.Elements[i], nil
+}
+
+func (l *ListValue) Contains(value RuntimeValue) (bool, error) {
+	for _, el := range l.Elements {
+		if valuesEqual(el, value) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (l *ListValue) Len() (int, error) {
+	return len(l.Elements), nil
+}
+
+func (l *ListValue) SetIndex(index RuntimeValue, value RuntimeValue) (RuntimeValue, error) {
+	i, err := resolveIndex(index, len(l.Elements))
+	if err != nil {
+		return nil, err
+	}
+
+	l.Elements[i] = value
+	return value, nil
+}
+
+func (l *ListValue) Slice(start, end, step RuntimeValue) (RuntimeValue, error) {
+	indices, err := sliceIndices(start, end, step, len(l.Elements))
+	if err != nil {
+		return nil, err
+	}
+
+	els := make([]RuntimeValue, 0, len(indices))
+	for _, i := range indices {
+		els = append(els, l.Elements[i])
+	}
+
+	return NewListValue(els), nil
+}
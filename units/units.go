@@ -0,0 +1,228 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dimension holds the powers of the base quantities: length, mass, time,
+// electric current, temperature, amount of substance and luminous intensity
+type Dimension [7]int
+
+var (
+	length      = Dimension{1, 0, 0, 0, 0, 0, 0}
+	mass        = Dimension{0, 1, 0, 0, 0, 0, 0}
+	duration    = Dimension{0, 0, 1, 0, 0, 0, 0}
+	current     = Dimension{0, 0, 0, 1, 0, 0, 0}
+	temperature = Dimension{0, 0, 0, 0, 1, 0, 0}
+	amount      = Dimension{0, 0, 0, 0, 0, 1, 0}
+	luminosity  = Dimension{0, 0, 0, 0, 0, 0, 1}
+
+	area      = Dimension{2, 0, 0, 0, 0, 0, 0}
+	volume    = Dimension{3, 0, 0, 0, 0, 0, 0}
+	frequency = Dimension{0, 0, -1, 0, 0, 0, 0}
+	speed     = Dimension{1, 0, -1, 0, 0, 0, 0}
+	force     = Dimension{1, 1, -2, 0, 0, 0, 0}
+	energy    = Dimension{2, 1, -2, 0, 0, 0, 0}
+	power     = Dimension{2, 1, -3, 0, 0, 0, 0}
+	pressure  = Dimension{-1, 1, -2, 0, 0, 0, 0}
+	charge    = Dimension{0, 0, 1, 1, 0, 0, 0}
+	voltage   = Dimension{2, 1, -3, -1, 0, 0, 0}
+	impedance = Dimension{2, 1, -3, -2, 0, 0, 0}
+)
+
+// definition is the size of a unit in SI base units
+type definition struct {
+	factor float64
+	dim    Dimension
+}
+
+// definitions are the known units, 'in' and 'd' are left out as they
+// already mean the 'in' keyword and the decimal suffix
+var definitions = map[string]definition{
+	"m":     {1, length},
+	"km":    {1e3, length},
+	"cm":    {1e-2, length},
+	"mm":    {1e-3, length},
+	"um":    {1e-6, length},
+	"nm":    {1e-9, length},
+	"mi":    {1609.344, length},
+	"yd":    {0.9144, length},
+	"ft":    {0.3048, length},
+	"inch":  {0.0254, length},
+	"nmi":   {1852, length},
+	"kg":    {1, mass},
+	"g":     {1e-3, mass},
+	"mg":    {1e-6, mass},
+	"tonne": {1e3, mass},
+	"lb":    {0.45359237, mass},
+	"oz":    {0.028349523125, mass},
+	"s":     {1, duration},
+	"ms":    {1e-3, duration},
+	"us":    {1e-6, duration},
+	"ns":    {1e-9, duration},
+	"min":   {60, duration},
+	"h":     {3600, duration},
+	"day":   {86400, duration},
+	"week":  {604800, duration},
+	"A":     {1, current},
+	"mA":    {1e-3, current},
+	"K":     {1, temperature},
+	"mol":   {1, amount},
+	"cd":    {1, luminosity},
+	"ha":    {1e4, area},
+	"L":     {1e-3, volume},
+	"mL":    {1e-6, volume},
+	"Hz":    {1, frequency},
+	"kHz":   {1e3, frequency},
+	"MHz":   {1e6, frequency},
+	"GHz":   {1e9, frequency},
+	"mph":   {0.44704, speed},
+	"kph":   {1 / 3.6, speed},
+	"knot":  {1852.0 / 3600, speed},
+	"N":     {1, force},
+	"kN":    {1e3, force},
+	"J":     {1, energy},
+	"kJ":    {1e3, energy},
+	"cal":   {4.184, energy},
+	"kcal":  {4184, energy},
+	"Wh":    {3600, energy},
+	"kWh":   {3.6e6, energy},
+	"W":     {1, power},
+	"kW":    {1e3, power},
+	"MW":    {1e6, power},
+	"Pa":    {1, pressure},
+	"kPa":   {1e3, pressure},
+	"bar":   {1e5, pressure},
+	"atm":   {101325, pressure},
+	"C":     {1, charge},
+	"V":     {1, voltage},
+	"ohm":   {1, impedance},
+}
+
+// IsUnit reports whether name is a known unit
+func IsUnit(name string) bool {
+	_, found := definitions[name]
+	return found
+}
+
+// Term is a unit raised to a power, like the 's^-2' of 'm/s^2'
+type Term struct {
+	Name  string
+	Power int
+}
+
+// Unit is a product of terms, the empty unit belongs to plain numbers
+type Unit []Term
+
+// Mul returns the product of two units, the powers of a repeated unit
+// are added up and the units whose power drops to 0 are removed
+func (u Unit) Mul(other Unit) Unit {
+	res := make(Unit, len(u))
+	copy(res, u)
+
+	for _, t := range other {
+		found := false
+		for i := range res {
+			if res[i].Name == t.Name {
+				res[i].Power += t.Power
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			res = append(res, t)
+		}
+	}
+
+	simplified := make(Unit, 0, len(res))
+	for _, t := range res {
+		if t.Power != 0 {
+			simplified = append(simplified, t)
+		}
+	}
+
+	return simplified
+}
+
+// Pow raises every term of the unit to n
+func (u Unit) Pow(n int) Unit {
+	res := make(Unit, 0, len(u))
+	for _, t := range u {
+		if t.Power*n != 0 {
+			res = append(res, Term{Name: t.Name, Power: t.Power * n})
+		}
+	}
+
+	return res
+}
+
+func (u Unit) Div(other Unit) Unit {
+	return u.Mul(other.Pow(-1))
+}
+
+// Dimension returns the powers of the base quantities measured by the unit
+func (u Unit) Dimension() Dimension {
+	var dim Dimension
+	for _, t := range u {
+		def := definitions[t.Name]
+		for i := range dim {
+			dim[i] += def.dim[i] * t.Power
+		}
+	}
+
+	return dim
+}
+
+// Factor returns the size of the unit in SI base units
+func (u Unit) Factor() float64 {
+	factor := 1.0
+	for _, t := range u {
+		def := definitions[t.Name]
+		for i := 0; i < t.Power; i++ {
+			factor *= def.factor
+		}
+		for i := 0; i > t.Power; i-- {
+			factor /= def.factor
+		}
+	}
+
+	return factor
+}
+
+// IsDimensionless reports whether the unit measures plain numbers, like 'km/m'
+func (u Unit) IsDimensionless() bool {
+	return u.Dimension() == Dimension{}
+}
+
+// String writes the unit like 'kg*m/s^2'
+func (u Unit) String() string {
+	var num, den []string
+
+	for _, t := range u {
+		power := max(t.Power, -t.Power)
+
+		str := t.Name
+		if power != 1 {
+			str += fmt.Sprintf("^%d", power)
+		}
+
+		if t.Power > 0 {
+			num = append(num, str)
+		} else {
+			den = append(den, str)
+		}
+	}
+
+	if len(num) == 0 {
+		num = []string{"1"}
+	}
+
+	str := strings.Join(num, "*")
+	for _, d := range den {
+		str += "/" + d
+	}
+
+	return str
+}
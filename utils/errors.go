@@ -1,13 +1,22 @@
 package utils
 
 import (
-	"errors"
 	"fmt"
 )
 
+// Error keeps the name and the details of an error apart, so the runtime
+// can turn it into an error value
+type Error struct {
+	Name    string
+	Details string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Name, e.Details)
+}
+
 func baseError(errorName string, details string) error {
-	errorMsg := fmt.Sprintf("%s: %s", errorName, details)
-	return errors.New(errorMsg)
+	return &Error{Name: errorName, Details: details}
 }
 
 func IllegalCharError(details string) error {